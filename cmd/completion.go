@@ -1,18 +1,21 @@
 package cmd
 
 import (
-	"path/filepath"
-
 	"github.com/MSmaili/hetki/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
-func getWorkspaceNames() ([]string, error) {
-	configDir, err := manifest.GetConfigDir()
+// workspacePaths maps the names of all named workspaces to their files.
+func workspacePaths() (map[string]string, error) {
+	dirs, err := manifest.WorkspaceDirs()
 	if err != nil {
 		return nil, err
 	}
-	paths, err := manifest.ScanWorkspaces(filepath.Join(configDir, "workspaces"))
+	return manifest.ScanWorkspaceDirs(dirs)
+}
+
+func getWorkspaceNames() ([]string, error) {
+	paths, err := workspacePaths()
 	if err != nil {
 		return nil, err
 	}
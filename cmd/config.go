@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MSmaili/hetki/internal/config"
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or change user settings",
+	Long: `Show or change settings in the global config file (config.yaml in the
+config directory). Settings become the defaults of the matching flags;
+flags given on the command line still win.
+
+Examples:
+  hetki config list
+  hetki config get strategy
+  hetki config set list_format tree
+  hetki config set marker ""        # Unset`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print a setting",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runConfigGet,
+	ValidArgsFunction: completeConfigKeys,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:               "set <key> <value>",
+	Short:             "Change a setting (an empty value unsets it)",
+	Args:              cobra.ExactArgs(2),
+	RunE:              runConfigSet,
+	ValidArgsFunction: completeConfigKeys,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	value, err := cfg.Get(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	path, err := config.Path()
+	if err != nil {
+		return fmt.Errorf("getting config path: %w", err)
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Set(args[0], args[1]); err != nil {
+		return err
+	}
+	if err := cfg.Save(path); err != nil {
+		return err
+	}
+
+	if args[1] == "" {
+		logger.Success("Unset %s", args[0])
+	} else {
+		logger.Success("Set %s = %s", args[0], args[1])
+	}
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	path, err := config.Path()
+	if err != nil {
+		return fmt.Errorf("getting config path: %w", err)
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	logger.Verbose("# %s", path)
+	for _, key := range config.Keys() {
+		value, _ := cfg.Get(key)
+		if value == "" {
+			value = "(unset)"
+		}
+		fmt.Printf("%s = %s\n", key, value)
+	}
+	return nil
+}
+
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	keys := make([]string, 0, len(config.Keys()))
+	for _, key := range config.Keys() {
+		keys = append(keys, key+"\t"+config.Usage(key))
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// applyUserConfig turns the settings in the global config file into flag
+// defaults, so anything given on the command line still takes precedence.
+func applyUserConfig(cfg *config.Config) {
+	setFlagDefault(rootCmd.PersistentFlags().Lookup("backend"), cfg.Backend)
+	setFlagDefault(listCmd.Flags().Lookup("format"), cfg.ListFormat)
+	setFlagDefault(listCmd.Flags().Lookup("marker"), cfg.Marker)
+	if cfg.Strategy == "force" {
+		setFlagDefault(startCmd.Flags().Lookup("force"), "true")
+	}
+	if cfg.Attach != nil {
+		setFlagDefault(startCmd.Flags().Lookup("attach"), strconv.FormatBool(*cfg.Attach))
+	}
+	if cfg.SwitchStart != nil {
+		setFlagDefault(switchCmd.Flags().Lookup("start"), strconv.FormatBool(*cfg.SwitchStart))
+	}
+}
+
+func setFlagDefault(f *pflag.Flag, value string) {
+	if f == nil || value == "" {
+		return
+	}
+	if err := f.Value.Set(value); err != nil {
+		logger.Warning("Ignoring config value %q for --%s: %v", value, f.Name, err)
+		return
+	}
+	f.DefValue = value
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/MSmaili/hetki/internal/converter"
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/MSmaili/hetki/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const (
+	driftMissing    = "missing"
+	driftExtra      = "extra"
+	driftChanged    = "changed"
+	driftMismatched = "mismatched"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [workspace-name-or-path]",
+	Short: "Show drift between a workspace and running sessions",
+	Long: `Show what differs between a workspace manifest and the running sessions.
+
+  + missing: created by 'hetki start'
+  - extra: removed by 'hetki start --force'
+  ~ mismatched: recreated by 'hetki start --force'
+
+Exits with a non-zero status when drift exists.
+
+Examples:
+  hetki diff                     # Local workspace
+  hetki diff myproject --format json
+  hetki diff --ignore-extra      # Only report what the manifest is missing`,
+	RunE: runDiff,
+}
+
+var (
+	diffFormat      string
+	diffIgnoreExtra bool
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "Output format: text, json")
+	diffCmd.Flags().BoolVar(&diffIgnoreExtra, "ignore-extra", false, "Ignore sessions and windows not in the workspace")
+
+	diffCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	diffCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeWorkspaceNames(cmd, args, toComplete)
+	}
+}
+
+type diffSession struct {
+	Name    string       `json:"name"`
+	Status  string       `json:"status"`
+	Windows []diffWindow `json:"windows,omitempty"`
+}
+
+type diffWindow struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Status       string `json:"status"`
+	DesiredPanes int    `json:"desired_panes,omitempty"`
+	ActualPanes  int    `json:"actual_panes,omitempty"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if diffFormat != "text" && diffFormat != "json" {
+		return fmt.Errorf("invalid format %q\nValid formats: text, json", diffFormat)
+	}
+
+	workspace, _, err := loadWorkspaceFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	b, err := detectBackend()
+	if err != nil {
+		return fmt.Errorf("failed to detect backend: %w", err)
+	}
+
+	result, err := b.QueryState()
+	if err != nil {
+		result = backend.StateResult{}
+	}
+
+	desired := converter.ManifestToState(workspace)
+	actual := converter.BackendResultToState(result)
+	sessions := diffSessions(state.Compare(desired, actual), desired, actual)
+
+	if diffFormat == "json" {
+		if sessions == nil {
+			sessions = []diffSession{}
+		}
+		if err := outputJSON(sessions); err != nil {
+			return err
+		}
+	} else {
+		printDiff(sessions)
+	}
+
+	if len(sessions) > 0 {
+		return fmt.Errorf("workspace has drifted: %d session(s) differ", len(sessions))
+	}
+	return nil
+}
+
+func diffSessions(diff state.Diff, desired, actual *state.State) []diffSession {
+	var sessions []diffSession
+
+	for _, name := range diff.Sessions.Missing {
+		s := diffSession{Name: name, Status: driftMissing}
+		for _, w := range desired.Sessions[name].Windows {
+			s.Windows = append(s.Windows, diffWindow{Name: w.Name, Path: w.Path, Status: driftMissing, DesiredPanes: state.PaneCount(w)})
+		}
+		sessions = append(sessions, s)
+	}
+
+	if !diffIgnoreExtra {
+		for _, name := range diff.Sessions.Extra {
+			s := diffSession{Name: name, Status: driftExtra}
+			for _, w := range actual.Sessions[name].Windows {
+				s.Windows = append(s.Windows, diffWindow{Name: w.Name, Path: w.Path, Status: driftExtra, ActualPanes: state.PaneCount(w)})
+			}
+			sessions = append(sessions, s)
+		}
+	}
+
+	for name, wd := range diff.Windows {
+		s := diffSession{Name: name, Status: driftChanged}
+		for _, w := range wd.Missing {
+			s.Windows = append(s.Windows, diffWindow{Name: w.Name, Path: w.Path, Status: driftMissing, DesiredPanes: state.PaneCount(&w)})
+		}
+		if !diffIgnoreExtra {
+			for _, w := range wd.Extra {
+				s.Windows = append(s.Windows, diffWindow{Name: w.Name, Path: w.Path, Status: driftExtra, ActualPanes: state.PaneCount(&w)})
+			}
+		}
+		for _, m := range wd.Mismatched {
+			s.Windows = append(s.Windows, diffWindow{
+				Name:         m.Desired.Name,
+				Path:         m.Desired.Path,
+				Status:       driftMismatched,
+				DesiredPanes: state.PaneCount(&m.Desired),
+				ActualPanes:  state.PaneCount(&m.Actual),
+			})
+		}
+		if len(s.Windows) == 0 {
+			continue
+		}
+		sort.SliceStable(s.Windows, func(i, j int) bool {
+			if s.Windows[i].Name != s.Windows[j].Name {
+				return s.Windows[i].Name < s.Windows[j].Name
+			}
+			return s.Windows[i].Path < s.Windows[j].Path
+		})
+		sessions = append(sessions, s)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Name < sessions[j].Name
+	})
+	return sessions
+}
+
+func printDiff(sessions []diffSession) {
+	if len(sessions) == 0 {
+		logger.Success("Workspace matches running sessions")
+		return
+	}
+
+	for _, s := range sessions {
+		printDriftLine(s.Status, "%s session %s", driftSymbol(s.Status), s.Name)
+		for _, w := range s.Windows {
+			path := manifest.ContractHome(w.Path)
+			switch w.Status {
+			case driftMismatched:
+				printDriftLine(w.Status, "    ~ window %s (%s): %s, expected %s", w.Name, path, pluralPanes(w.ActualPanes), pluralPanes(w.DesiredPanes))
+			case driftMissing:
+				printDriftLine(w.Status, "    + window %s (%s, %s)", w.Name, path, pluralPanes(w.DesiredPanes))
+			case driftExtra:
+				printDriftLine(w.Status, "    - window %s (%s, %s)", w.Name, path, pluralPanes(w.ActualPanes))
+			}
+		}
+	}
+}
+
+func printDriftLine(status, format string, args ...any) {
+	switch status {
+	case driftMissing:
+		logger.Success(format, args...)
+	case driftExtra:
+		logger.Error(format, args...)
+	case driftMismatched:
+		logger.Warning(format, args...)
+	default:
+		logger.Info(format, args...)
+	}
+}
+
+func driftSymbol(status string) string {
+	switch status {
+	case driftMissing:
+		return "+"
+	case driftExtra:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func pluralPanes(n int) string {
+	if n == 1 {
+		return "1 pane"
+	}
+	return fmt.Sprintf("%d panes", n)
+}
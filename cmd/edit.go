@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [workspace]",
+	Short: "Edit a workspace file in $EDITOR",
+	Long: `Open a workspace in $VISUAL or $EDITOR. Without an argument the local
+.hetki.yaml is edited.
+
+The changes are made on a temporary copy, which is loaded and validated when
+the editor exits. Only a valid workspace replaces the original file; when the
+copy has errors they are shown and the editor can be re-opened.
+
+Examples:
+  hetki edit            # Edit .hetki.yaml
+  hetki edit myproject  # Edit a named workspace`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	editCmd.ValidArgsFunction = completeWorkspaceNames
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	var nameOrPath string
+	if len(args) > 0 {
+		nameOrPath = args[0]
+	}
+
+	path, err := manifest.NewResolver().Resolve(nameOrPath)
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading workspace: %w", err)
+	}
+
+	// Keep the extension so the copy is parsed in the same format.
+	tmp, err := os.CreateTemp("", "hetki-*-"+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(original)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+
+	for {
+		if err := openInEditor(tmpPath); err != nil {
+			os.Remove(tmpPath)
+			return err
+		}
+
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("reading edited workspace: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			os.Remove(tmpPath)
+			logger.Info("No changes to %s", path)
+			return nil
+		}
+
+		verr := validateWorkspaceFile(tmpPath)
+		if verr == nil {
+			if err := writeFilePreservingMode(path, edited); err != nil {
+				return fmt.Errorf("writing workspace: %w\nHint: Your edits are kept in %s", err, tmpPath)
+			}
+			os.Remove(tmpPath)
+			logger.Success("Saved %s", path)
+			return nil
+		}
+
+		logger.Error("Invalid workspace: %v", verr)
+		if !confirm("Re-open the editor?", true) {
+			return fmt.Errorf("workspace not saved, %s is unchanged\nHint: Your edits are kept in %s", path, tmpPath)
+		}
+	}
+}
+
+func validateWorkspaceFile(path string) error {
+	workspace, err := manifest.NewFileLoader(path).Load()
+	if err != nil {
+		return err
+	}
+	if errs := manifest.Validate(workspace); len(errs) > 0 {
+		return manifest.ToError(errs)
+	}
+	return nil
+}
+
+// writeFilePreservingMode replaces path with data through a temporary file
+// in the same directory, so an interrupted write leaves the old contents in
+// place. A symlinked workspace keeps its link.
+func writeFilePreservingMode(path string, data []byte) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(mode)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// An empty answer picks def; a closed stdin counts as no.
+func confirm(question string, def bool) bool {
+	choices := "[y/N]"
+	if def {
+		choices = "[Y/n]"
+	}
+	fmt.Fprintf(os.Stderr, "%s %s ", question, choices)
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(os.Stderr)
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "":
+		return def
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
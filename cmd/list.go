@@ -3,14 +3,18 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 	"sync"
+	"text/template"
+	"time"
 
 	"github.com/MSmaili/hetki/internal/backend"
 	"github.com/MSmaili/hetki/internal/manifest"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -24,11 +28,20 @@ var listCmd = &cobra.Command{
 	Short: "List workspaces or sessions",
 	Long: `List workspace files or running tmux sessions.
 
+With --format=template=<text>, each line is rendered from a Go template, one
+per session, window or pane depending on --windows and --panes. Fields:
+  .Workspace .Session .Window .WindowIndex .Pane .Target
+  .Path .Command .Layout .Active .Attached .Created .Activity
+.WindowIndex is the tmux window index, or -1 for a workspace window that
+does not set one.
+
 Examples:
   hetki list                              # List workspace names
   hetki list workspaces --sessions        # workspace:session
   hetki list sessions --windows --format=tree  # Pretty tree view
-  hetki list sessions --windows --format=json  # JSON output`,
+  hetki list sessions --windows --format=json  # JSON output
+  hetki list sessions -w --format='template={{.Target}}	{{.Command}}	{{.Path}}'
+  hetki list sessions --format='template={{.Session}} {{.Activity.Format "15:04"}}'`,
 	RunE: runList,
 }
 
@@ -47,36 +60,93 @@ func init() {
 	listCmd.Flags().BoolVarP(&listSessions, "sessions", "s", false, "Include sessions")
 	listCmd.Flags().BoolVarP(&listWindows, "windows", "w", false, "Include windows")
 	listCmd.Flags().BoolVarP(&listPanes, "panes", "p", false, "Include panes")
-	listCmd.Flags().StringVarP(&listFormat, "format", "f", "flat", "Output format: flat, indent, tree, json")
+	listCmd.Flags().StringVarP(&listFormat, "format", "f", "flat", "Output format: flat, indent, tree, json, template=<go template>")
 	listCmd.Flags().StringVarP(&listDelimiter, "delimiter", "d", ":", "Delimiter for flat output")
 	listCmd.Flags().BoolVarP(&listCurrent, "current", "c", false, "Only show current session")
 	listCmd.Flags().StringVarP(&listMarker, "marker", "m", "", "Prefix for current session/window (e.g. '➤ ')")
 
 	listCmd.ValidArgs = []string{"workspaces", "sessions"}
 	listCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"flat", "indent", "tree", "json"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"flat", "indent", "tree", "json", "template="}, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
 	})
 }
 
+// listTemplate is set when --format is template=<text>.
+var listTemplate *template.Template
+
+// listItem is a session to print. Active marks the current session, window
+// or pane on the level that is printed, which is where the marker goes.
 type listItem struct {
-	Name    string
-	Windows []listWindow
+	Name      string
+	Workspace string
+	Session   string
+	Path      string
+	Command   string
+	Active    bool
+	Attached  bool
+	Created   time.Time
+	Activity  time.Time
+	Windows   []listWindow
 }
 
 type listWindow struct {
-	Name       string
-	Panes      []int
+	Name string
+	// Index is the tmux window index, -1 when a workspace leaves it to tmux.
+	Index      int
+	Layout     string
+	Path       string
+	Command    string
+	Active     bool
+	Panes      []listPane
 	ActivePane int
 }
 
+type listPane struct {
+	Index   int
+	Path    string
+	Command string
+}
+
+// listRow is what a --format template is executed with.
+type listRow struct {
+	Workspace   string
+	Session     string
+	Window      string
+	WindowIndex int
+	Pane        int
+	Target      string
+	Path        string
+	Command     string
+	Layout      string
+	Active      bool
+	Attached    bool
+	Created     time.Time
+	Activity    time.Time
+}
+
 type jsonSession struct {
-	Name    string       `json:"name"`
-	Windows []jsonWindow `json:"windows,omitempty"`
+	Name     string       `json:"name"`
+	Attached bool         `json:"attached,omitempty"`
+	Created  *time.Time   `json:"created,omitempty"`
+	Activity *time.Time   `json:"activity,omitempty"`
+	Windows  []jsonWindow `json:"windows,omitempty"`
 }
 
 type jsonWindow struct {
-	Name  string `json:"name"`
-	Panes []int  `json:"panes,omitempty"`
+	Name    string `json:"name"`
+	Index   *int   `json:"index,omitempty"`
+	Layout  string `json:"layout,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Command string `json:"command,omitempty"`
+	Panes   []int  `json:"panes,omitempty"`
+	// PaneDetails describes the panes listed by index in Panes.
+	PaneDetails []jsonPane `json:"pane_details,omitempty"`
+}
+
+type jsonPane struct {
+	Index   int    `json:"index"`
+	Path    string `json:"path,omitempty"`
+	Command string `json:"command,omitempty"`
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -97,8 +167,14 @@ func runList(cmd *cobra.Command, args []string) error {
 
 func validateListFlags(mode string) error {
 	validFormats := map[string]bool{"flat": true, "indent": true, "tree": true, "json": true}
-	if !validFormats[listFormat] {
-		return fmt.Errorf("invalid format %q\nValid formats: flat, indent, tree, json\nExample: hetki list --format=tree", listFormat)
+	if text, ok := strings.CutPrefix(listFormat, "template="); ok {
+		tmpl, err := template.New("format").Option("missingkey=error").Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid format template: %w\nExample: hetki list sessions -w --format='template={{.Target}} {{.Path}}'", err)
+		}
+		listTemplate = tmpl
+	} else if !validFormats[listFormat] {
+		return fmt.Errorf("invalid format %q\nValid formats: flat, indent, tree, json, template=<text>\nExample: hetki list --format=tree", listFormat)
 	}
 	if mode == "workspaces" {
 		if listWindows && !listSessions {
@@ -118,12 +194,12 @@ func validateListFlags(mode string) error {
 }
 
 func listWorkspaceFiles() error {
-	configDir, err := manifest.GetConfigDir()
+	dirs, err := manifest.WorkspaceDirs()
 	if err != nil {
-		return fmt.Errorf("failed to get config directory: %w", err)
+		return fmt.Errorf("failed to get workspace directories: %w", err)
 	}
 
-	paths, err := manifest.ScanWorkspaces(configDir + "/workspaces")
+	paths, err := manifest.ScanWorkspaceDirs(dirs)
 	if err != nil {
 		return fmt.Errorf("failed to scan workspaces: %w", err)
 	}
@@ -171,7 +247,7 @@ func listWorkspaceFiles() error {
 }
 
 func listActiveSessions() error {
-	b, err := backend.Detect()
+	b, err := detectBackend()
 	if err != nil {
 		return fmt.Errorf("failed to detect backend: %w\nHint: Make sure a supported multiplexer is running", err)
 	}
@@ -207,14 +283,33 @@ func workspaceToItems(name string, ws *manifest.Workspace) []listItem {
 	items := make([]listItem, 0, len(ws.Sessions))
 
 	for _, sess := range ws.Sessions {
-		item := listItem{Name: name + ":" + sess.Name}
+		item := listItem{Name: name + ":" + sess.Name, Workspace: name, Session: sess.Name, Path: sess.Root}
 		if listWindows {
 			for _, win := range sess.Windows {
-				lw := listWindow{Name: win.Name}
+				lw := listWindow{
+					Name:       win.Name,
+					Index:      -1,
+					Layout:     win.Layout,
+					Path:       win.Path,
+					Command:    win.Command,
+					ActivePane: -1,
+				}
+				if win.Index != nil {
+					lw.Index = *win.Index
+				}
+				if lw.Path == "" {
+					lw.Path = sess.Root
+				}
 				if listPanes {
-					paneCount := max(1, len(win.Panes))
-					for p := range paneCount {
-						lw.Panes = append(lw.Panes, p)
+					for p := range max(1, len(win.Panes)) {
+						lp := listPane{Index: p, Path: lw.Path, Command: win.Command}
+						if p < len(win.Panes) {
+							lp.Command = win.Panes[p].Command
+							if win.Panes[p].Path != "" {
+								lp.Path = win.Panes[p].Path
+							}
+						}
+						lw.Panes = append(lw.Panes, lp)
 					}
 				}
 				item.Windows = append(item.Windows, lw)
@@ -226,13 +321,29 @@ func workspaceToItems(name string, ws *manifest.Workspace) []listItem {
 }
 
 func sessionToItem(sess backend.Session, active backend.ActiveContext) listItem {
-	item := listItem{Name: applyMarker(sess.Name, sess.Name == active.Session && !listWindows)}
+	pane := activePane(activeWindow(sess.Windows).Panes)
+	item := listItem{
+		Name:     sess.Name,
+		Session:  sess.Name,
+		Active:   sess.Name == active.Session && !listWindows,
+		Path:     pane.Path,
+		Command:  pane.Command,
+		Attached: sess.Attached,
+		Created:  sess.Created,
+		Activity: sess.Activity,
+	}
 
 	if listWindows {
 		for _, win := range sess.Windows {
-			isActiveWindow := sess.Name == active.Session && win.Name == active.Window
+			isActiveWindow := sess.Name == active.Session && win.Active
+			pane := activePane(win.Panes)
 			lw := listWindow{
-				Name:       applyMarker(win.Name, isActiveWindow && !listPanes),
+				Name:       win.Name,
+				Index:      win.Index,
+				Layout:     win.Layout,
+				Path:       pane.Path,
+				Command:    pane.Command,
+				Active:     isActiveWindow && !listPanes,
 				ActivePane: -1,
 			}
 
@@ -241,7 +352,7 @@ func sessionToItem(sess backend.Session, active backend.ActiveContext) listItem
 					lw.ActivePane = active.Pane
 				}
 				for _, p := range win.Panes {
-					lw.Panes = append(lw.Panes, p.Index)
+					lw.Panes = append(lw.Panes, listPane{Index: p.Index, Path: p.Path, Command: p.Command})
 				}
 			}
 			item.Windows = append(item.Windows, lw)
@@ -258,6 +369,9 @@ func applyMarker(name string, isActive bool) string {
 }
 
 func outputItems(items []listItem) error {
+	if listTemplate != nil {
+		return outputTemplate(itemsToRows(items))
+	}
 	if listFormat == "json" {
 		return outputJSON(itemsToJSON(items))
 	}
@@ -272,30 +386,120 @@ func outputItems(items []listItem) error {
 func itemsToJSON(items []listItem) []jsonSession {
 	out := make([]jsonSession, len(items))
 	for i, item := range items {
-		out[i] = jsonSession{Name: item.Name}
+		out[i] = jsonSession{
+			Name:     applyMarker(item.Name, item.Active),
+			Attached: item.Attached,
+			Created:  timeOrNil(item.Created),
+			Activity: timeOrNil(item.Activity),
+		}
 		if len(item.Windows) > 0 {
 			out[i].Windows = make([]jsonWindow, len(item.Windows))
 			for j, w := range item.Windows {
-				out[i].Windows[j] = jsonWindow{Name: w.Name}
-				if len(w.Panes) > 0 {
-					out[i].Windows[j].Panes = w.Panes
+				jw := jsonWindow{Name: applyMarker(w.Name, w.Active), Layout: w.Layout, Path: w.Path, Command: w.Command}
+				if w.Index >= 0 {
+					jw.Index = &w.Index
 				}
+				for _, p := range w.Panes {
+					jw.Panes = append(jw.Panes, p.Index)
+					jw.PaneDetails = append(jw.PaneDetails, jsonPane{Index: p.Index, Path: p.Path, Command: p.Command})
+				}
+				out[i].Windows[j] = jw
 			}
 		}
 	}
 	return out
 }
 
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// itemsToRows flattens items into one row per line of flat output: per
+// pane with --panes, per window with --windows, else per session.
+func itemsToRows(items []listItem) []listRow {
+	var rows []listRow
+	for _, item := range items {
+		session := listRow{
+			Workspace: item.Workspace,
+			Session:   item.Session,
+			Target:    item.Session,
+			Path:      item.Path,
+			Command:   item.Command,
+			Active:    item.Active,
+			Attached:  item.Attached,
+			Created:   item.Created,
+			Activity:  item.Activity,
+		}
+		if len(item.Windows) == 0 {
+			rows = append(rows, session)
+			continue
+		}
+		for _, w := range item.Windows {
+			window := session
+			window.Window = w.Name
+			window.WindowIndex = w.Index
+			window.Target = item.Session + listDelimiter + w.Name
+			window.Path, window.Command, window.Layout = w.Path, w.Command, w.Layout
+			window.Active = w.Active
+			if len(w.Panes) == 0 {
+				rows = append(rows, window)
+				continue
+			}
+			for _, p := range w.Panes {
+				pane := window
+				pane.Pane = p.Index
+				pane.Target = fmt.Sprintf("%s%s%d", window.Target, listDelimiter, p.Index)
+				pane.Path, pane.Command = p.Path, p.Command
+				pane.Active = w.ActivePane == p.Index
+				rows = append(rows, pane)
+			}
+		}
+	}
+	return rows
+}
+
+func outputTemplate(rows []listRow) error {
+	for _, row := range rows {
+		var b strings.Builder
+		if err := listTemplate.Execute(&b, row); err != nil {
+			return fmt.Errorf("format template: %w", err)
+		}
+		fmt.Println(b.String())
+	}
+	return nil
+}
+
 func outputJSON(data any) error {
-	out, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
+	// Plan steps read like "a -> b", keep them as written.
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
 		return fmt.Errorf("marshaling json: %w", err)
 	}
-	fmt.Println(string(out))
+	return nil
+}
+
+func outputYAML(data any) error {
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshaling yaml: %w", err)
+	}
+	fmt.Print(string(out))
 	return nil
 }
 
 func outputNames(names []string) error {
+	if listTemplate != nil {
+		rows := make([]listRow, len(names))
+		for i, n := range names {
+			rows[i] = listRow{Workspace: n}
+		}
+		return outputTemplate(rows)
+	}
 	if listFormat == "json" {
 		return outputJSON(names)
 	}
@@ -321,50 +525,38 @@ func (f *formatter) printItem(item listItem, lastItem bool) {
 func (f *formatter) printFlat(item listItem) {
 	d := listDelimiter
 	if len(item.Windows) == 0 {
-		fmt.Println(item.Name)
+		fmt.Println(applyMarker(item.Name, item.Active))
 		return
 	}
 	for _, win := range item.Windows {
 		line := fmt.Sprintf("%s%s%s", item.Name, d, win.Name)
-
-		if listMarker != "" && strings.HasPrefix(win.Name, listMarker) {
-			cleanName := strings.TrimPrefix(win.Name, listMarker)
-			line = listMarker + fmt.Sprintf("%s%s%s", item.Name, d, cleanName)
-		}
-
 		if len(win.Panes) == 0 {
-			fmt.Println(line)
+			fmt.Println(applyMarker(line, win.Active))
 			continue
 		}
 		for _, p := range win.Panes {
-			paneStr := fmt.Sprintf("%s%s%d", line, d, p)
-
-			if listMarker != "" && win.ActivePane == p {
-				cleanLine := strings.TrimPrefix(line, listMarker)
-				paneStr = listMarker + fmt.Sprintf("%s%s%d", cleanLine, d, p)
-			}
-
-			fmt.Println(paneStr)
+			fmt.Println(applyMarker(fmt.Sprintf("%s%s%d", line, d, p.Index), win.ActivePane == p.Index))
 		}
 	}
 }
 
 func (f *formatter) printTree(item listItem, lastItem bool) {
+	name := applyMarker(item.Name, item.Active)
 	if len(item.Windows) == 0 {
-		f.printNode(item.Name, depthRoot, lastItem)
+		f.printNode(name, depthRoot, lastItem)
 		return
 	}
 
-	f.printNode(item.Name, depthRoot, lastItem)
+	f.printNode(name, depthRoot, lastItem)
 	for i, win := range item.Windows {
 		lastWin := i == len(item.Windows)-1
 		if len(win.Panes) == 0 {
-			f.printNode(win.Name, depthWindow, lastWin)
+			f.printNode(applyMarker(win.Name, win.Active), depthWindow, lastWin)
 			continue
 		}
 		f.printNode(win.Name, depthWindow, lastWin)
 		for j, p := range win.Panes {
-			f.printNode(fmt.Sprintf("%d", p), depthPane, j == len(win.Panes)-1)
+			f.printNode(fmt.Sprintf("%d", p.Index), depthPane, j == len(win.Panes)-1)
 		}
 	}
 }
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/MSmaili/hetki/internal/scaffold"
+	"github.com/spf13/cobra"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a named workspace for a project",
+	Long: `Create a named workspace for the project in the current directory (or
+--dir). The windows are proposed from the files found there: go.mod,
+package.json, Cargo.toml, pyproject.toml and Makefile each add windows such
+as test, server or build. Use --template to pick them yourself.
+
+Examples:
+  hetki new api                       # Detect the project type
+  hetki new api --template go,make    # Use templates instead
+  hetki new notes --template blank --edit`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNew,
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init [name]",
+	Short: "Create a .hetki.yaml workspace in the current directory",
+	Long: `Create a project-local .hetki.yaml in the current directory (or --dir),
+like 'hetki new' does for named workspaces. The session is named after the
+directory unless a name is given. Paths are relative to the file, so it can
+be committed with the project.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInit,
+}
+
+var (
+	newTemplates []string
+	newDir       string
+	newEdit      bool
+	newForce     bool
+)
+
+func init() {
+	for _, c := range []*cobra.Command{newCmd, initCmd} {
+		rootCmd.AddCommand(c)
+		c.Flags().StringSliceVarP(&newTemplates, "template", "t", nil, "Templates to use instead of detecting the project type: "+strings.Join(scaffold.Names(), ", "))
+		c.Flags().StringVar(&newDir, "dir", "", "Project directory (default: current directory)")
+		c.Flags().BoolVarP(&newEdit, "edit", "e", false, "Open the new workspace in $EDITOR")
+		c.Flags().BoolVarP(&newForce, "force", "f", false, "Overwrite an existing workspace file")
+		c.RegisterFlagCompletionFunc("template", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return scaffold.Names(), cobra.ShellCompDirectiveNoFileComp
+		})
+		c.RegisterFlagCompletionFunc("dir", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		})
+	}
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	dir, err := projectDir()
+	if err != nil {
+		return err
+	}
+
+	path, err := manifest.NewResolver().NamedPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	return scaffoldWorkspace(args[0], manifest.ContractHome(dir), dir, path)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	dir, err := projectDir()
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(dir)
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	return scaffoldWorkspace(name, ".", dir, filepath.Join(dir, ".hetki"+manifest.DefaultExt))
+}
+
+func projectDir() (string, error) {
+	dir := newDir
+	if dir == "" {
+		dir = "."
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving project directory: %w", err)
+	}
+	if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("project directory not found: %s", abs)
+	}
+	return abs, nil
+}
+
+func scaffoldWorkspace(name, root, dir, path string) error {
+	if _, err := os.Stat(path); err == nil && !newForce {
+		return fmt.Errorf("workspace already exists: %s\nHint: Use --force to overwrite it, or 'hetki start %s' to use it", path, name)
+	}
+
+	templates := newTemplates
+	if len(templates) == 0 {
+		templates = scaffold.Detect(dir)
+		if len(templates) > 0 {
+			logger.Info("Detected %s project", strings.Join(templates, ", "))
+		}
+	}
+
+	workspace, err := scaffold.New(name, root, dir, templates)
+	if err != nil {
+		return err
+	}
+	if errs := manifest.Validate(workspace); len(errs) > 0 {
+		return manifest.ToError(errs)
+	}
+
+	if err := manifest.Write(workspace, path); err != nil {
+		return fmt.Errorf("writing workspace: %w", err)
+	}
+	logger.Success("Created %s", path)
+
+	if newEdit {
+		return openInEditor(path)
+	}
+	return nil
+}
+
+func openInEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	// The editor may carry arguments, e.g. "code --wait".
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		editor, fields = "vi", []string{"vi"}
+	}
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
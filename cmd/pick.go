@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/MSmaili/hetki/internal/picker"
+	"github.com/MSmaili/hetki/internal/plan"
+	"github.com/spf13/cobra"
+)
+
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Pick a session, window, pane or workspace to switch to",
+	Long: `Open a fuzzy finder over the running sessions, windows and panes and the
+saved workspaces that are not running. Type to filter, move with the arrow
+keys or Ctrl-N/Ctrl-P and press Enter to switch. Picking a workspace starts
+it first.
+
+The selection is previewed on the right when the terminal is wide enough.
+Inside tmux the picker fits in a popup:
+
+  bind-key f display-popup -E -w 80% -h 60% hetki pick
+
+Examples:
+  hetki pick
+  hetki pick --type sessions,workspaces
+  hetki pick --no-preview`,
+	Args: cobra.NoArgs,
+	RunE: runPick,
+}
+
+var pickTypeNames = []string{"sessions", "windows", "panes", "workspaces"}
+
+var (
+	pickTypes     []string
+	pickNoPreview bool
+)
+
+func init() {
+	rootCmd.AddCommand(pickCmd)
+	pickCmd.Flags().StringSliceVarP(&pickTypes, "type", "t", pickTypeNames, "What to pick from: "+strings.Join(pickTypeNames, ", "))
+	pickCmd.Flags().BoolVar(&pickNoPreview, "no-preview", false, "Do not preview the selection")
+
+	pickCmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return pickTypeNames, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// pickChoice is what happens when an item is picked: switching to a running
+// target or starting a workspace.
+type pickChoice struct {
+	target    backend.Target
+	workspace string
+}
+
+func runPick(cmd *cobra.Command, args []string) error {
+	for _, t := range pickTypes {
+		if !slices.Contains(pickTypeNames, t) {
+			return fmt.Errorf("invalid type %q\nValid types: %s", t, strings.Join(pickTypeNames, ", "))
+		}
+	}
+
+	b, detectErr := detectBackend()
+	var sessions []backend.Session
+	if detectErr == nil {
+		if result, err := b.QueryState(); err == nil {
+			sessions = result.Sessions
+		}
+	}
+	slices.SortFunc(sessions, func(a, b backend.Session) int { return strings.Compare(a.Name, b.Name) })
+
+	items, choices := runningPickItems(b, sessions)
+	if slices.Contains(pickTypes, "workspaces") {
+		wsItems, wsChoices, err := workspacePickItems(sessions)
+		if err != nil {
+			return err
+		}
+		items = append(items, wsItems...)
+		choices = append(choices, wsChoices...)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("nothing to pick from\nHint: Start a session or create a workspace with 'hetki new'")
+	}
+
+	i, err := picker.Run(items, picker.Options{Preview: !pickNoPreview})
+	if errors.Is(err, picker.ErrCancelled) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w\nHint: 'hetki pick' needs a terminal; use 'hetki list sessions -w | fzf | hetki switch' in scripts", err)
+	}
+
+	if detectErr != nil {
+		return fmt.Errorf("failed to detect backend: %w", detectErr)
+	}
+	choice := choices[i]
+	if choice.workspace != "" {
+		return startPickedWorkspace(b, choice.workspace)
+	}
+	if err := b.Switch(choice.target); err != nil {
+		return fmt.Errorf("switch to %q: %w", choice.target, err)
+	}
+	return nil
+}
+
+func runningPickItems(b backend.Backend, sessions []backend.Session) ([]picker.Item, []pickChoice) {
+	var items []picker.Item
+	var choices []pickChoice
+	add := func(label, detail string, target backend.Target, paneID string) {
+		items = append(items, picker.Item{Label: label, Detail: detail, Preview: panePreview(b, paneID)})
+		choices = append(choices, pickChoice{target: target})
+	}
+
+	for _, sess := range sessions {
+		if slices.Contains(pickTypes, "sessions") {
+			detail := "session, 1 window"
+			if len(sess.Windows) != 1 {
+				detail = fmt.Sprintf("session, %d windows", len(sess.Windows))
+			}
+			add(sess.Name, detail, backend.Target{Session: sess.Name}, activePane(activeWindow(sess.Windows).Panes).ID)
+		}
+		for _, win := range sess.Windows {
+			label := sess.Name + ":" + win.Name
+			target := backend.Target{Session: sess.Name, WindowIndex: &win.Index}
+			pane := activePane(win.Panes)
+			if slices.Contains(pickTypes, "windows") {
+				add(label, "window, "+pane.Command, target, pane.ID)
+			}
+			if !slices.Contains(pickTypes, "panes") || len(win.Panes) < 2 {
+				continue
+			}
+			for _, p := range win.Panes {
+				paneTarget := target
+				paneTarget.Pane = &p.Index
+				add(fmt.Sprintf("%s:%d", label, p.Index), "pane, "+p.Command, paneTarget, p.ID)
+			}
+		}
+	}
+	return items, choices
+}
+
+// workspacePickItems lists the named workspaces with at least one session
+// that is not running.
+func workspacePickItems(sessions []backend.Session) ([]picker.Item, []pickChoice, error) {
+	paths, err := workspacePaths()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to scan workspaces: %w", err)
+	}
+
+	var items []picker.Item
+	var choices []pickChoice
+	for _, name := range sortedKeys(paths) {
+		if ws, err := manifest.NewFileLoader(paths[name]).Load(); err == nil && allRunning(ws, sessions) {
+			continue
+		}
+		items = append(items, picker.Item{Label: name, Detail: "workspace", Preview: filePreview(paths[name])})
+		choices = append(choices, pickChoice{workspace: name})
+	}
+	return items, choices, nil
+}
+
+func allRunning(ws *manifest.Workspace, sessions []backend.Session) bool {
+	for _, s := range ws.Sessions {
+		if !slices.ContainsFunc(sessions, func(running backend.Session) bool { return running.Name == s.Name }) {
+			return false
+		}
+	}
+	return true
+}
+
+func startPickedWorkspace(b backend.Backend, name string) error {
+	workspace, workspacePath, err := loadWorkspaceFromArgs([]string{name})
+	if err != nil {
+		return err
+	}
+	// Picking only adds what is missing, whatever strategy start uses.
+	p, err := buildPlan(b, workspace, workspacePath, &plan.MergeStrategy{})
+	if err != nil {
+		return err
+	}
+	return executePlan(b, p, workspace, planOptions{noAttach: startDetached()})
+}
+
+func activeWindow(windows []backend.Window) backend.Window {
+	for _, w := range windows {
+		if w.Active {
+			return w
+		}
+	}
+	if len(windows) > 0 {
+		return windows[0]
+	}
+	return backend.Window{}
+}
+
+func activePane(panes []backend.Pane) backend.Pane {
+	for _, p := range panes {
+		if p.Active {
+			return p
+		}
+	}
+	if len(panes) > 0 {
+		return panes[0]
+	}
+	return backend.Pane{}
+}
+
+// panePreview shows the bottom of a pane, where the prompt or the latest
+// output is.
+func panePreview(b backend.Backend, id string) func(width, height int) string {
+	if b == nil || id == "" {
+		return nil
+	}
+	return func(width, height int) string {
+		content, err := b.CapturePane(id)
+		if err != nil {
+			return ""
+		}
+		lines := strings.Split(strings.TrimRight(content, " \n"), "\n")
+		return strings.Join(lines[max(0, len(lines)-height):], "\n")
+	}
+}
+
+func filePreview(path string) func(width, height int) string {
+	return func(width, height int) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/MSmaili/hetki/internal/plan"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore [snapshot-id]",
+	Short: "Rebuild sessions from a snapshot",
+	Long: `Rebuild sessions from the latest snapshot, or from the one whose id starts
+with snapshot-id. Sessions and windows that already exist are left alone.
+
+Examples:
+  hetki restore                   # Restore the latest snapshot
+  hetki restore 20260301-1200     # Restore a chosen snapshot
+  hetki restore --dry-run         # Show what would be created
+  hetki restore --no-attach       # Restore without attaching`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRestore,
+}
+
+var (
+	restoreDryRun   bool
+	restoreNoAttach bool
+)
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().BoolVarP(&restoreDryRun, "dry-run", "d", false, "Print plan without executing")
+	restoreCmd.Flags().BoolVar(&restoreNoAttach, "no-attach", false, "Restore the sessions detached")
+
+	restoreCmd.ValidArgsFunction = completeSnapshotIDs
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	store, err := openSnapshotStore()
+	if err != nil {
+		return err
+	}
+
+	var id string
+	if len(args) > 0 {
+		id = args[0]
+	}
+
+	snap, err := store.Find(id)
+	if err != nil {
+		return err
+	}
+
+	workspace, err := store.Load(snap)
+	if err != nil {
+		return fmt.Errorf("loading snapshot %s: %w", snap.ID, err)
+	}
+	if errs := manifest.Validate(workspace); len(errs) > 0 {
+		return manifest.ToError(errs)
+	}
+
+	b, err := detectBackend()
+	if err != nil {
+		return fmt.Errorf("failed to detect backend: %w", err)
+	}
+
+	// Restoring only adds what is missing, whatever strategy start uses.
+	p, err := buildPlan(b, workspace, snap.Path, &plan.MergeStrategy{})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Restoring snapshot %s", snap.ID)
+	return executePlan(b, p, workspace, planOptions{dryRun: restoreDryRun, noAttach: restoreNoAttach})
+}
+
+func completeSnapshotIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	store, err := openSnapshotStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	snaps, err := store.List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ids := make([]string, len(snaps))
+	for i, s := range snaps {
+		ids[i] = s.ID
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
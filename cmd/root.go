@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/MSmaili/hetki/internal/config"
 	"github.com/MSmaili/hetki/internal/logger"
 	"github.com/spf13/cobra"
 )
@@ -27,15 +30,61 @@ It supports:
 - Named and local workspaces
 - Templates for reusable configurations`,
 	Version: Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		logger.SetVerbose(verbose)
+	},
 }
 
+var (
+	verbose     bool
+	backendName string
+)
+
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("hetki version %s\ncommit: %s\nbuilt: %s\n", Version, GitCommit, BuildDate))
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show verbose output")
+	rootCmd.PersistentFlags().StringVar(&backendName, "backend", "", "Multiplexer backend to use (default: detect)")
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	if err := config.MigrateLegacyDir(); err != nil {
+		logger.Warning("Could not migrate configuration: %v", err)
+	}
+	if cfg, err := config.Load(); err != nil {
+		logger.Warning("Ignoring config file: %v", err)
+	} else {
+		applyUserConfig(cfg)
+	}
+
+	err := rootCmd.Execute()
+	closeBackends()
+	if err != nil {
 		logger.Error("%v", err)
 		os.Exit(1)
 	}
 }
+
+// openBackends holds the backends detected while running a command, so
+// connections they keep open are closed when it is done.
+var openBackends []backend.Backend
+
+func detectBackend() (backend.Backend, error) {
+	b, err := backend.Detect(backendName)
+	if err == nil {
+		openBackends = append(openBackends, b)
+	}
+	return b, err
+}
+
+func closeBackends() {
+	for _, b := range openBackends {
+		closeBackend(b)
+	}
+	openBackends = nil
+}
+
+func closeBackend(b backend.Backend) {
+	if closer, ok := b.(io.Closer); ok {
+		closer.Close()
+	}
+}
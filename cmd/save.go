@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"slices"
 
 	"github.com/MSmaili/hetki/internal/backend"
 	"github.com/MSmaili/hetki/internal/logger"
 	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/MSmaili/hetki/internal/proc"
 	"github.com/spf13/cobra"
 )
 
@@ -18,14 +19,26 @@ var saveCmd = &cobra.Command{
 	Long: `Save the current tmux session state to a workspace configuration file.
 
 By default, saves the current session. Use --all to save all sessions.
-Use -n to specify a workspace name or -p for an explicit path.`,
+Use -n to specify a workspace name or -p for an explicit path.
+
+Commands running in panes are saved too, except interactive shells (bash,
+zsh, fish and the like). --exclude-commands skips more commands on top of
+those. --include-commands saves only the commands it names, shells included.
+
+With --with-scrollback, the history of every pane is stored in a sidecar
+directory next to the workspace file (work.scrollback for work.yaml). Use
+hetki start --restore-scrollback to replay it.`,
 	RunE: runSave,
 }
 
 var (
-	savePath string
-	saveName string
-	saveAll  bool
+	savePath        string
+	saveName        string
+	saveAll         bool
+	saveInclude     []string
+	saveExclude     []string
+	saveScrollbacks bool
+	defaultExcluded = []string{"bash", "zsh", "fish", "sh", "dash", "ksh", "tcsh", "csh", "nu", "pwsh", "login"}
 )
 
 func init() {
@@ -33,6 +46,9 @@ func init() {
 	saveCmd.Flags().StringVarP(&savePath, "path", "p", "", "Path to save workspace file")
 	saveCmd.Flags().StringVarP(&saveName, "name", "n", "", "Name for the workspace")
 	saveCmd.Flags().BoolVar(&saveAll, "all", false, "Save all tmux sessions")
+	saveCmd.Flags().StringSliceVar(&saveInclude, "include-commands", nil, "Only save pane commands with these names, even shells (default: all but shells)")
+	saveCmd.Flags().StringSliceVar(&saveExclude, "exclude-commands", nil, "Also skip pane commands with these names, besides shells")
+	saveCmd.Flags().BoolVar(&saveScrollbacks, "with-scrollback", false, "Also save the scrollback history of every pane")
 
 	saveCmd.ValidArgs = []string{"."}
 	saveCmd.RegisterFlagCompletionFunc("name", completeWorkspaceNames)
@@ -43,7 +59,7 @@ func runSave(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	b, err := backend.Detect()
+	b, err := detectBackend()
 	if err != nil {
 		return fmt.Errorf("failed to detect backend: %w\nHint: Make sure a supported multiplexer is running", err)
 	}
@@ -58,7 +74,14 @@ func runSave(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return saveWorkspace(sessions, outputPath)
+	if err := saveWorkspace(sessions, outputPath); err != nil {
+		return err
+	}
+
+	if saveScrollbacks {
+		return saveWorkspaceScrollback(b, sessions, outputPath)
+	}
+	return nil
 }
 
 func validateSaveFlags() error {
@@ -127,20 +150,46 @@ func saveWorkspace(sessions []backend.Session, outputPath string) error {
 		return fmt.Errorf("resolving absolute path: %w", err)
 	}
 
-	workspace := convertToWorkspace(sessions)
+	workspace := convertToWorkspace(sessions, filepath.Dir(absPath))
+
+	if err := writeWorkspace(workspace, absPath); err != nil {
+		return fmt.Errorf("writing workspace: %w", err)
+	}
+
+	logger.Success("Saved to %s", absPath)
+	return nil
+}
+
+// writeWorkspace saves workspace to path. An existing YAML file is updated
+// in place so hand-written fields and comments survive; other formats fall
+// back to replacing saved sessions wholesale.
+func writeWorkspace(workspace *manifest.Workspace, path string) error {
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		if src, err := os.ReadFile(path); err == nil {
+			merged, err := manifest.MergeYAML(src, filepath.Dir(path), workspace, saveAll)
+			if err == nil {
+				return os.WriteFile(path, merged, 0644)
+			}
+			logger.Warning("Could not update %s in place, rewriting it: %v", path, err)
+		}
+	}
 
 	if !saveAll {
-		loader := manifest.NewFileLoader(absPath)
+		loader := manifest.NewFileLoader(path)
 		if existing, err := loader.Load(); err == nil {
 			workspace = mergeWorkspaces(existing, workspace)
 		}
 	}
+	return manifest.Write(workspace, path)
+}
 
-	if err := manifest.Write(workspace, absPath); err != nil {
-		return fmt.Errorf("writing workspace: %w", err)
+func saveWorkspaceScrollback(b backend.Backend, sessions []backend.Session, outputPath string) error {
+	dir := scrollbackDir(outputPath)
+	if err := saveScrollback(b, sessions, dir); err != nil {
+		return fmt.Errorf("saving scrollback: %w", err)
 	}
 
-	logger.Success("Saved to %s", absPath)
+	logger.Success("Saved scrollback to %s", dir)
 	return nil
 }
 
@@ -160,53 +209,143 @@ func mergeWorkspaces(existing, new *manifest.Workspace) *manifest.Workspace {
 	return existing
 }
 
-func convertToWorkspace(sessions []backend.Session) *manifest.Workspace {
+// convertToWorkspace builds a manifest from live sessions. Session roots
+// inside dir, the directory the manifest is written to, are saved relative
+// to it so the file keeps working when the directory moves.
+func convertToWorkspace(sessions []backend.Session, dir string) *manifest.Workspace {
 	ws := &manifest.Workspace{
 		Sessions: make([]manifest.Session, len(sessions)),
 	}
 
 	for i, sess := range sessions {
+		root := sessionRoot(sess.Windows)
 		ws.Sessions[i] = manifest.Session{
 			Name:    sess.Name,
-			Windows: convertWindows(sess.Windows),
+			Root:    savedPath(dir, root),
+			Windows: convertWindows(sess.Windows, root),
 		}
 	}
 
 	return ws
 }
 
-func convertWindows(windows []backend.Window) []manifest.Window {
+func convertWindows(windows []backend.Window, root string) []manifest.Window {
 	result := make([]manifest.Window, len(windows))
 	for i, w := range windows {
-		result[i] = manifest.Window{
-			Name: w.Name,
-			Path: contractHomePath(w.Path),
+		result[i] = manifest.Window{Name: w.Name}
+		// A window in the root itself inherits it.
+		if path := savedPath(root, w.Path); path != "." {
+			result[i].Path = path
 		}
 		if len(w.Panes) > 1 {
-			result[i].Panes = convertPanes(w.Panes)
+			result[i].Layout = w.Layout
+			result[i].Panes = convertPanes(w.Panes, root)
+		} else if len(w.Panes) == 1 {
+			result[i].Command = paneCommand(w.Panes[0])
+		}
+	}
+	return result
+}
+
+func convertPanes(panes []backend.Pane, root string) []manifest.Pane {
+	result := make([]manifest.Pane, len(panes))
+	for i, p := range panes {
+		result[i] = manifest.Pane{
+			Path:    savedPath(root, p.Path),
+			Command: paneCommand(p),
+			Zoom:    p.Zoom,
 		}
 	}
 	return result
 }
 
-func contractHomePath(path string) string {
-	home, err := os.UserHomeDir()
-	if err != nil || home == "" {
-		return path
+// savedPath returns path relative to root when it lies inside it, and with
+// the home directory contracted otherwise.
+func savedPath(root, path string) string {
+	if root != "" {
+		if rel, ok := manifest.RelativePath(root, path); ok {
+			return rel
+		}
 	}
-	if strings.HasPrefix(path, home+"/") {
-		return "~" + strings.TrimPrefix(path, home)
+	return manifest.ContractHome(path)
+}
+
+// sessionRoot picks the directory the paths of a session are saved relative
+// to: the git repository containing all of them, or else their deepest
+// common directory. It returns "" when that would only be / or the home
+// directory, since relative paths gain nothing there.
+func sessionRoot(windows []backend.Window) string {
+	var paths []string
+	for _, w := range windows {
+		paths = append(paths, w.Path)
+		for _, p := range w.Panes {
+			paths = append(paths, p.Path)
+		}
 	}
-	if path == home {
-		return "~"
+
+	home, _ := os.UserHomeDir()
+	root := commonDir(paths)
+	if repo := gitRoot(root); repo != "" && repo != home {
+		root = repo
 	}
-	return path
+
+	if root == "/" || root == home {
+		return ""
+	}
+	return root
 }
 
-func convertPanes(panes []backend.Pane) []manifest.Pane {
-	result := make([]manifest.Pane, len(panes))
-	for i, p := range panes {
-		result[i] = manifest.Pane{Path: contractHomePath(p.Path)}
+func commonDir(paths []string) string {
+	var common string
+	for _, p := range paths {
+		if p == "" || !filepath.IsAbs(p) {
+			continue
+		}
+		p = filepath.Clean(p)
+		if common == "" {
+			common = p
+			continue
+		}
+		for {
+			if _, ok := manifest.RelativePath(common, p); ok {
+				break
+			}
+			common = filepath.Dir(common)
+		}
 	}
-	return result
+	return common
+}
+
+// gitRoot returns the top of the git work tree containing dir, if any.
+func gitRoot(dir string) string {
+	for d := dir; d != "" && d != "/"; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+	}
+	return ""
+}
+
+// paneCommand returns the command line running in p, or "" when nothing
+// worth restoring runs there.
+func paneCommand(p backend.Pane) string {
+	if p.Command == "" || !commandAllowed(p.Command) {
+		return ""
+	}
+	if cmdline, ok := proc.CommandLine(p.PID, p.Command); ok {
+		return cmdline
+	}
+	return p.Command
+}
+
+// commandAllowed reports whether the command called name is saved. Shells
+// are left out unless --include-commands names them.
+func commandAllowed(name string) bool {
+	if slices.Contains(saveExclude, name) {
+		return false
+	}
+	if len(saveInclude) > 0 {
+		return slices.Contains(saveInclude, name)
+	}
+	return !slices.Contains(defaultExcluded, name)
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/MSmaili/hetki/internal/state"
+)
+
+// scrollbackDir returns the sidecar directory holding pane history for the
+// workspace file at path, e.g. work.scrollback next to work.yaml. The result
+// is absolute since panes may start in any directory.
+func scrollbackDir(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".scrollback"
+}
+
+// scrollbackFile names the file for a pane by its window and pane position,
+// which is how the panes are laid out in the manifest.
+func scrollbackFile(dir, session string, window, pane int) string {
+	return filepath.Join(dir, url.PathEscape(session), fmt.Sprintf("%d.%d.txt", window, pane))
+}
+
+func saveScrollback(b backend.Backend, sessions []backend.Session, dir string) error {
+	for _, sess := range sessions {
+		sessDir := filepath.Join(dir, url.PathEscape(sess.Name))
+		if err := os.RemoveAll(sessDir); err != nil {
+			return fmt.Errorf("clearing scrollback for %s: %w", sess.Name, err)
+		}
+		if err := os.MkdirAll(sessDir, 0o755); err != nil {
+			return fmt.Errorf("creating scrollback directory: %w", err)
+		}
+
+		for w, win := range sess.Windows {
+			for p, pane := range win.Panes {
+				content, err := b.CapturePane(pane.ID)
+				if err != nil {
+					return fmt.Errorf("capturing %s:%s pane %d: %w", sess.Name, win.Name, p, err)
+				}
+				if content == "" {
+					continue
+				}
+				file := scrollbackFile(dir, sess.Name, w, p)
+				if err := os.WriteFile(file, []byte(content+"\n"), 0o600); err != nil {
+					return fmt.Errorf("writing scrollback: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// attachScrollback points every desired pane that has saved history in dir
+// at its file. Windows without explicit panes get their implicit first pane.
+func attachScrollback(desired *state.State, workspace *manifest.Workspace, dir string) {
+	for _, sess := range workspace.Sessions {
+		session, ok := desired.Sessions[sess.Name]
+		if !ok {
+			continue
+		}
+		for w, win := range session.Windows {
+			if len(win.Panes) == 0 {
+				file := scrollbackFile(dir, sess.Name, w, 0)
+				if fileExists(file) {
+					win.Panes = []*state.Pane{{Path: win.Path, Scrollback: file}}
+				}
+				continue
+			}
+			for p, pane := range win.Panes {
+				if file := scrollbackFile(dir, sess.Name, w, p); fileExists(file) {
+					pane.Scrollback = file
+				}
+			}
+		}
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
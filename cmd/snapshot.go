@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save a snapshot of all running sessions",
+	Long: `Save the state of all running sessions into the snapshot store
+(snapshots in the config directory), so a crashed tmux server can be rebuilt with
+'hetki restore'.
+
+A snapshot is only written when the state changed since the last one. Old
+snapshots are pruned according to --keep and --max-age.
+
+Examples:
+  hetki snapshot                          # Take one snapshot
+  hetki snapshot --daemon --interval 5m   # Keep taking snapshots until stopped
+  hetki snapshot --list                   # Show stored snapshots`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshot,
+}
+
+var (
+	snapshotDaemon   bool
+	snapshotInterval time.Duration
+	snapshotKeep     int
+	snapshotMaxAge   time.Duration
+	snapshotList     bool
+)
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.Flags().BoolVar(&snapshotDaemon, "daemon", false, "Keep running and take a snapshot every --interval")
+	snapshotCmd.Flags().DurationVar(&snapshotInterval, "interval", 5*time.Minute, "Time between snapshots in daemon mode")
+	snapshotCmd.Flags().IntVar(&snapshotKeep, "keep", 50, "Number of snapshots to keep (0 for no limit)")
+	snapshotCmd.Flags().DurationVar(&snapshotMaxAge, "max-age", 0, "Remove snapshots older than this, e.g. 168h (0 for no limit)")
+	snapshotCmd.Flags().BoolVarP(&snapshotList, "list", "l", false, "List stored snapshots")
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	store, err := openSnapshotStore()
+	if err != nil {
+		return err
+	}
+
+	if snapshotList {
+		return listSnapshots(store)
+	}
+
+	if !snapshotDaemon {
+		b, err := detectBackend()
+		if err != nil {
+			return fmt.Errorf("failed to detect backend: %w\nHint: Make sure a supported multiplexer is running", err)
+		}
+		return takeSnapshot(b, store)
+	}
+
+	if snapshotInterval <= 0 {
+		return fmt.Errorf("invalid interval %s\nExample: hetki snapshot --daemon --interval 5m", snapshotInterval)
+	}
+	return runSnapshotDaemon(store)
+}
+
+func openSnapshotStore() (*snapshot.Store, error) {
+	dir, err := snapshot.DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("getting snapshot dir: %w", err)
+	}
+	return snapshot.NewStore(dir), nil
+}
+
+func takeSnapshot(b backend.Backend, store *snapshot.Store) error {
+	result, err := b.QueryState()
+	if err != nil {
+		return fmt.Errorf("failed to query sessions: %w", err)
+	}
+	if len(result.Sessions) == 0 {
+		logger.Info("No sessions to snapshot")
+		return nil
+	}
+
+	now := time.Now()
+	snap, saved, err := store.Save(convertToWorkspace(result.Sessions, ""), now)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		logger.Info("No changes since snapshot %s", snap.ID)
+		return nil
+	}
+	logger.Success("Saved snapshot %s (%d sessions)", snap.ID, len(result.Sessions))
+
+	removed, err := store.Prune(snapshotKeep, snapshotMaxAge, now)
+	for _, s := range removed {
+		logger.Verbose("Removed snapshot %s", s.ID)
+	}
+	return err
+}
+
+// runSnapshotDaemon takes a snapshot every interval until interrupted.
+// Failures are logged rather than returned so a restarting tmux server does
+// not stop the daemon.
+func runSnapshotDaemon(store *snapshot.Store) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Taking snapshots every %s (stop with Ctrl-C)", snapshotInterval)
+
+	ticker := time.NewTicker(snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := takeDaemonSnapshot(store); err != nil {
+			logger.Warning("Snapshot failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// takeDaemonSnapshot detects the backend afresh and closes it again, so the
+// daemon holds no connection to the server between snapshots and does not
+// keep it running once the user's last session is gone.
+func takeDaemonSnapshot(store *snapshot.Store) error {
+	b, err := backend.Detect(backendName)
+	if err != nil {
+		return nil
+	}
+	defer closeBackend(b)
+	return takeSnapshot(b, store)
+}
+
+func listSnapshots(store *snapshot.Store) error {
+	snaps, err := store.List()
+	if err != nil {
+		return fmt.Errorf("listing snapshots: %w", err)
+	}
+	if len(snaps) == 0 {
+		logger.Info("No snapshots in %s", store.Dir)
+		return nil
+	}
+
+	for _, snap := range snaps {
+		sessions := "?"
+		if ws, err := store.Load(snap); err == nil {
+			sessions = fmt.Sprint(len(ws.Sessions))
+		}
+		logger.Plain("%s  %s  %s session(s)", snap.ID, snap.CreatedAt.Local().Format(time.DateTime), sessions)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/MSmaili/hetki/internal/backend"
@@ -13,8 +14,16 @@ import (
 )
 
 var (
-	dryRun bool
-	force  bool
+	dryRun        bool
+	force         bool
+	transactional bool
+	strict        bool
+	planFormat    string
+	restoreScroll bool
+
+	attachAfterStart bool
+	noAttach         bool
+	attachTarget     string
 )
 
 var startCmd = &cobra.Command{
@@ -26,8 +35,20 @@ var startCmd = &cobra.Command{
 func init() {
 	startCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Print plan without executing")
 	startCmd.Flags().BoolVarP(&force, "force", "f", false, "Kill extra sessions/windows and recreate mismatched")
+	startCmd.Flags().BoolVar(&transactional, "transactional", false, "Roll back completed actions if the plan fails")
+	startCmd.Flags().BoolVar(&strict, "strict", false, "Fail if the workspace still differs from the manifest after applying")
+	startCmd.Flags().BoolVar(&restoreScroll, "restore-scrollback", false, "Replay pane history saved with 'hetki save --with-scrollback' into new panes")
+	startCmd.Flags().StringVar(&planFormat, "format", "commands", "Dry-run output format: commands, text, json, yaml")
+	startCmd.Flags().BoolVar(&attachAfterStart, "attach", true, "Attach to the workspace after starting it")
+	startCmd.Flags().BoolVar(&noAttach, "no-attach", false, "Start the workspace detached")
+	startCmd.Flags().StringVar(&attachTarget, "target", "", "Attach to session[:window[:pane]] instead of the manifest's attach target")
+	startCmd.MarkFlagsMutuallyExclusive("attach", "no-attach")
 	rootCmd.AddCommand(startCmd)
 
+	startCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"commands", "text", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
 	startCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) > 0 {
 			return nil, cobra.ShellCompDirectiveNoFileComp
@@ -37,22 +58,63 @@ func init() {
 }
 
 func runStart(cmd *cobra.Command, args []string) error {
-	workspace, _, err := loadWorkspaceFromArgs(args)
+	if err := validateStartFlags(cmd); err != nil {
+		return err
+	}
+
+	workspace, workspacePath, err := loadWorkspaceFromArgs(args)
 	if err != nil {
 		return err
 	}
+	if attachTarget != "" {
+		if err := manifest.ValidateTarget(workspace, attachTarget); err != nil {
+			return err
+		}
+	}
 
-	b, err := backend.Detect()
+	b, err := detectBackend()
 	if err != nil {
 		return fmt.Errorf("failed to detect backend: %w", err)
 	}
 
-	p, err := buildPlan(b, workspace)
+	p, err := buildPlan(b, workspace, workspacePath, selectStrategy())
 	if err != nil {
 		return err
 	}
 
-	return executePlan(b, p, workspace)
+	return executePlan(b, p, workspace, startOptions())
+}
+
+// planOptions control how executePlan applies a plan and where it attaches.
+type planOptions struct {
+	dryRun   bool
+	noAttach bool
+	// force is set when the plan was built with the force strategy.
+	force bool
+	// target overrides the workspace's attach target.
+	target string
+}
+
+func startOptions() planOptions {
+	return planOptions{dryRun: dryRun, noAttach: startDetached(), force: force, target: attachTarget}
+}
+
+// startDetached reports whether --attach and --no-attach, or their defaults
+// from the config, leave the workspace detached.
+func startDetached() bool {
+	return noAttach || !attachAfterStart
+}
+
+func validateStartFlags(cmd *cobra.Command) error {
+	switch planFormat {
+	case "commands", "text", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q\nValid formats: commands, text, json, yaml", planFormat)
+	}
+	if cmd.Flags().Changed("format") && !dryRun {
+		return fmt.Errorf("--format only works with --dry-run\nExample: hetki start --dry-run --format json")
+	}
+	return nil
 }
 
 func loadWorkspaceFromArgs(args []string) (*manifest.Workspace, string, error) {
@@ -66,6 +128,7 @@ func loadWorkspaceFromArgs(args []string) (*manifest.Workspace, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
+	logger.Verbose("Using workspace %s", workspacePath)
 
 	loader := manifest.NewFileLoader(workspacePath)
 	workspace, err := loader.Load()
@@ -80,8 +143,11 @@ func loadWorkspaceFromArgs(args []string) (*manifest.Workspace, string, error) {
 	return workspace, workspacePath, nil
 }
 
-func buildPlan(b backend.Backend, workspace *manifest.Workspace) (*plan.Plan, error) {
+func buildPlan(b backend.Backend, workspace *manifest.Workspace, workspacePath string, strategy plan.Strategy) (*plan.Plan, error) {
 	desired := converter.ManifestToState(workspace)
+	if restoreScroll {
+		attachScrollback(desired, workspace, scrollbackDir(workspacePath))
+	}
 
 	result, err := b.QueryState()
 	if err != nil {
@@ -91,8 +157,6 @@ func buildPlan(b backend.Backend, workspace *manifest.Workspace) (*plan.Plan, er
 
 	diff := state.Compare(desired, actual)
 	planDiff := converter.StateDiffToPlanDiff(diff, desired)
-
-	strategy := selectStrategy()
 	return strategy.Plan(planDiff), nil
 }
 
@@ -103,29 +167,171 @@ func selectStrategy() plan.Strategy {
 	return &plan.MergeStrategy{}
 }
 
-func executePlan(b backend.Backend, p *plan.Plan, workspace *manifest.Workspace) error {
+func executePlan(b backend.Backend, p *plan.Plan, workspace *manifest.Workspace, opts planOptions) error {
+	if opts.dryRun {
+		return printDryRun(b, p)
+	}
+
 	if p.IsEmpty() {
 		logger.Info("Workspace already up to date")
-		return attachToSession(b, workspace)
+		return attachToSession(b, workspace, opts)
+	}
+
+	if err := applyPlan(b, p); err != nil {
+		return fmt.Errorf("failed to execute plan: %w\nHint: Check tmux server logs or try with --dry-run to see planned actions", err)
 	}
 
-	if dryRun {
-		printDryRun(b, p)
+	if err := verifyWorkspace(b, workspace, opts.force); err != nil {
+		return err
+	}
+
+	return attachToSession(b, workspace, opts)
+}
+
+// verifyWorkspace queries the backend again and reports whatever the plan
+// did not bring in line with the manifest.
+func verifyWorkspace(b backend.Backend, workspace *manifest.Workspace, force bool) error {
+	result, err := b.QueryState()
+	if err != nil {
+		return fmt.Errorf("verifying workspace: %w", err)
+	}
+
+	desired := converter.ManifestToState(workspace)
+	diff := state.Compare(desired, converter.BackendResultToState(result))
+
+	drift := residualDrift(diff, force)
+	if len(drift) == 0 {
 		return nil
 	}
 
-	if err := b.Apply(toBackendActions(p.Actions)); err != nil {
-		return fmt.Errorf("failed to execute plan: %w\nHint: Check tmux server logs or try with --dry-run to see planned actions", err)
+	logger.Warning("Workspace differs from manifest after applying:")
+	for _, line := range drift {
+		logger.Warning("  - %s", line)
+	}
+
+	if strict {
+		return fmt.Errorf("workspace verification failed: %d difference(s) remain", len(drift))
+	}
+	return nil
+}
+
+// residualDrift describes the parts of diff the selected strategy should
+// have resolved. Extra sessions and windows and mismatched windows only
+// count with force, since the merge strategy leaves them alone on purpose.
+func residualDrift(diff state.Diff, force bool) []string {
+	var drift []string
+
+	for _, name := range diff.Sessions.Missing {
+		drift = append(drift, fmt.Sprintf("session %s is missing", name))
+	}
+	if force {
+		for _, name := range diff.Sessions.Extra {
+			drift = append(drift, fmt.Sprintf("session %s was not removed", name))
+		}
+	}
+
+	for _, sessionName := range sortedKeys(diff.Windows) {
+		wd := diff.Windows[sessionName]
+		for _, w := range wd.Missing {
+			drift = append(drift, fmt.Sprintf("window %s:%s (%s) is missing", sessionName, w.Name, w.Path))
+		}
+		if force {
+			for _, m := range wd.Mismatched {
+				drift = append(drift, fmt.Sprintf("window %s:%s has %d pane(s), expected %d",
+					sessionName, m.Desired.Name, state.PaneCount(&m.Actual), state.PaneCount(&m.Desired)))
+			}
+			for _, w := range wd.Extra {
+				drift = append(drift, fmt.Sprintf("window %s:%s was not removed", sessionName, w.Name))
+			}
+		}
 	}
 
-	return attachToSession(b, workspace)
+	return drift
 }
 
-func printDryRun(b backend.Backend, p *plan.Plan) {
+func applyPlan(b backend.Backend, p *plan.Plan) error {
+	err := b.Apply(toBackendActions(p.Actions))
+
+	var applyErr *backend.ApplyError
+	if !errors.As(err, &applyErr) {
+		if err != nil && transactional {
+			logger.Warning("Cannot roll back: %s did not report which actions completed", b.Name())
+		}
+		return err
+	}
+	printApplyError(applyErr)
+
+	if !transactional {
+		return err
+	}
+	if rbErr := rollback(b, applyErr.Completed); rbErr != nil {
+		return errors.Join(err, fmt.Errorf("rollback failed: %w", rbErr))
+	}
+	return err
+}
+
+func rollback(b backend.Backend, completed []backend.Action) error {
+	undo, irreversible := plan.Rollback(toPlanActions(completed))
+
+	for _, a := range irreversible {
+		logger.Warning("Cannot roll back: %s", backend.Describe(a))
+	}
+	if undo.IsEmpty() {
+		return nil
+	}
+
+	logger.Info("Rolling back %d actions...", len(undo.Actions))
+	if err := b.Apply(toBackendActions(undo.Actions)); err != nil {
+		var applyErr *backend.ApplyError
+		if errors.As(err, &applyErr) {
+			printApplyError(applyErr)
+		}
+		return err
+	}
+	logger.Success("Rolled back")
+	return nil
+}
+
+func printApplyError(e *backend.ApplyError) {
+	logger.Info("Plan stopped after %d of %d actions:", len(e.Completed), len(e.Completed)+1+len(e.Skipped))
+	for _, a := range e.Completed {
+		logger.Success("  ✓ %s", backend.Describe(a))
+	}
+	logger.Error("  ✗ %s", backend.Describe(e.Failed))
+	for _, a := range e.Skipped {
+		logger.Plain("  - %s (skipped)", backend.Describe(a))
+	}
+}
+
+func printDryRun(b backend.Backend, p *plan.Plan) error {
+	switch planFormat {
+	case "json":
+		return outputJSON(planDocument{Actions: p.Steps()})
+	case "yaml":
+		return outputYAML(planDocument{Actions: p.Steps()})
+	}
+
+	if p.IsEmpty() {
+		logger.Info("Workspace already up to date")
+		return nil
+	}
+
+	if planFormat == "text" {
+		for _, step := range p.Steps() {
+			fmt.Println(step.Comment)
+		}
+		return nil
+	}
+
 	logger.Info("Dry run - actions to execute:")
 	for _, line := range b.DryRun(toBackendActions(p.Actions)) {
 		logger.Plain("  %s", line)
 	}
+	return nil
+}
+
+type planDocument struct {
+	Actions []plan.Step `json:"actions" yaml:"actions"`
 }
 
 func toBackendActions(actions []plan.Action) []backend.Action {
@@ -136,9 +342,37 @@ func toBackendActions(actions []plan.Action) []backend.Action {
 	return result
 }
 
-func attachToSession(b backend.Backend, workspace *manifest.Workspace) error {
-	if len(workspace.Sessions) > 0 {
-		return b.Attach(workspace.Sessions[0].Name)
+func toPlanActions(actions []backend.Action) []plan.Action {
+	result := make([]plan.Action, 0, len(actions))
+	for _, a := range actions {
+		if pa, ok := a.(plan.Action); ok {
+			result = append(result, pa)
+		}
+	}
+	return result
+}
+
+// attachToSession attaches to opts.target, or else to where the manifest
+// asks for with attach or focus, or else to the first session.
+func attachToSession(b backend.Backend, workspace *manifest.Workspace, opts planOptions) error {
+	if opts.noAttach {
+		return nil
+	}
+	var t backend.Target
+	var err error
+	target := opts.target
+	if target != "" {
+		t, err = backend.ParseTarget(target, ":")
+	} else if target = workspace.AttachTarget(); target != "" {
+		t, err = manifest.ParseAttachTarget(target)
+	} else {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := b.Switch(t); err != nil {
+		return fmt.Errorf("attach to %q: %w", target, err)
 	}
 	return nil
 }
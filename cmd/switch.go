@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"unicode"
 
 	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/MSmaili/hetki/internal/plan"
 	"github.com/spf13/cobra"
 )
 
@@ -20,13 +24,34 @@ The target can be passed as an argument or piped from stdin:
   hetki switch dev
   hetki switch dev:editor
   hetki switch dev:editor:0
-  hetki list sessions -w | fzf | hetki switch`,
+  hetki list sessions -w | fzf | hetki switch
+
+Windows can be given by name or index and panes by their position,
+counting from 0. Names may be shortened to a unique prefix or a fuzzy match
+(dev:ed, dv:edr); an ambiguous name lists the candidates. Use the same
+--delimiter as 'hetki list' when piping its output:
+  hetki list sessions -w -d / | fzf | hetki switch -d /
+
+With --start, a session that is not running is started from the named
+workspace of the same name first, so one key binding both jumps to and
+launches projects:
+  hetki switch --start api
+  hetki config set switch_start true    # Make --start the default
+
+To choose interactively without fzf, use 'hetki pick'.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSwitch,
 }
 
+var (
+	switchDelimiter string
+	switchStart     bool
+)
+
 func init() {
 	rootCmd.AddCommand(switchCmd)
+	switchCmd.Flags().StringVarP(&switchDelimiter, "delimiter", "d", ":", "Delimiter between session, window and pane")
+	switchCmd.Flags().BoolVarP(&switchStart, "start", "s", false, "Start the workspace named like the session if it is not running")
 }
 
 func runSwitch(cmd *cobra.Command, args []string) error {
@@ -42,16 +67,70 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 		raw = line
 	}
 
-	target := parseTarget(raw)
-	if target == "" {
-		return fmt.Errorf("empty target")
+	target, err := backend.ParseTarget(stripMarkerPrefix(strings.TrimSpace(raw)), switchDelimiter)
+	if err != nil {
+		return err
 	}
 
-	b, err := backend.Detect()
+	b, err := detectBackend()
 	if err != nil {
 		return fmt.Errorf("failed to detect backend: %w", err)
 	}
 
+	if switchStart && !sessionRunning(b, target.Session) {
+		if path, err := namedWorkspacePath(target.Session); err == nil {
+			return startAndSwitch(b, path, target)
+		}
+	}
+
+	if err := b.Switch(target); err != nil {
+		return fmt.Errorf("switch to %q: %w", target, err)
+	}
+	return nil
+}
+
+func sessionRunning(b backend.Backend, name string) bool {
+	result, err := b.QueryState()
+	if err != nil {
+		return false
+	}
+	for _, s := range result.Sessions {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// startAndSwitch applies the workspace at path and switches to target. When
+// the workspace has no session named like the target, it switches to where
+// the workspace asks to attach instead.
+func startAndSwitch(b backend.Backend, path string, target backend.Target) error {
+	workspace, workspacePath, err := loadWorkspaceFromArgs([]string{path})
+	if err != nil {
+		return err
+	}
+	// Switching only adds what is missing, whatever strategy start uses.
+	p, err := buildPlan(b, workspace, workspacePath, &plan.MergeStrategy{})
+	if err != nil {
+		return err
+	}
+
+	logger.Verbose("Starting workspace %s", workspaceName(path))
+	if !p.IsEmpty() {
+		if err := applyPlan(b, p); err != nil {
+			return fmt.Errorf("failed to start workspace: %w", err)
+		}
+		if err := verifyWorkspace(b, workspace, false); err != nil {
+			return err
+		}
+	}
+
+	if !slices.ContainsFunc(workspace.Sessions, func(s manifest.Session) bool { return s.Name == target.Session }) {
+		if target, err = manifest.ParseAttachTarget(workspace.AttachTarget()); err != nil {
+			return err
+		}
+	}
 	if err := b.Switch(target); err != nil {
 		return fmt.Errorf("switch to %q: %w", target, err)
 	}
@@ -77,13 +156,6 @@ func readStdinLine() (string, error) {
 	return "", fmt.Errorf("empty stdin")
 }
 
-func parseTarget(raw string) string {
-	s := strings.TrimSpace(raw)
-	s = stripMarkerPrefix(s)
-	s = listToTmuxFormat(s)
-	return s
-}
-
 func stripMarkerPrefix(s string) string {
 	i := strings.IndexFunc(s, func(r rune) bool {
 		return r == ':' || unicode.IsLetter(r) || unicode.IsDigit(r)
@@ -93,11 +165,3 @@ func stripMarkerPrefix(s string) string {
 	}
 	return strings.TrimSpace(s)
 }
-
-func listToTmuxFormat(s string) string {
-	parts := strings.Split(s, ":")
-	if len(parts) == 3 {
-		return parts[0] + ":" + parts[1] + "." + parts[2]
-	}
-	return s
-}
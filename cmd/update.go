@@ -31,7 +31,6 @@ const (
 var (
 	updateFromSource bool
 	updateDryRun     bool
-	updateVerbose    bool
 )
 
 var updateCmd = &cobra.Command{
@@ -44,12 +43,9 @@ func init() {
 	rootCmd.AddCommand(updateCmd)
 	updateCmd.Flags().BoolVar(&updateFromSource, "source", false, "Build from source instead of using release")
 	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "Show what would be done without updating")
-	updateCmd.Flags().BoolVarP(&updateVerbose, "verbose", "v", false, "Show verbose output")
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
-	logger.SetVerbose(updateVerbose)
-
 	exePath, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("failed to resolve executable path: %w", err)
@@ -182,7 +178,7 @@ func (g *GoUpdater) Update(_ string) error {
 	logger.Info("Updating hetki...")
 
 	args := []string{"install"}
-	if updateVerbose {
+	if verbose {
 		args = append(args, "-v")
 	}
 	args = append(args, module)
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:     "workspace",
+	Aliases: []string{"ws"},
+	Short:   "Manage named workspaces",
+	Long: `Manage the named workspaces in the workspaces directory and the
+workspace_paths from the global config.
+
+Copies and renames stay in the directory of the source workspace. Give the
+new name an extension to change the format, e.g. 'hetki workspace cp api
+api.json' converts YAML to JSON.
+
+Examples:
+  hetki workspace show api
+  hetki workspace mv api backend
+  hetki workspace cp api api-staging
+  hetki workspace rm old-project`,
+}
+
+var workspaceShowCmd = &cobra.Command{
+	Use:               "show <name>",
+	Short:             "Print a workspace file",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runWorkspaceShow,
+	ValidArgsFunction: completeFirstWorkspace,
+}
+
+var workspacePathCmd = &cobra.Command{
+	Use:               "path <name>",
+	Short:             "Print the path of a workspace file",
+	Args:              cobra.ExactArgs(1),
+	RunE:              runWorkspacePath,
+	ValidArgsFunction: completeFirstWorkspace,
+}
+
+var workspaceRmCmd = &cobra.Command{
+	Use:               "rm <name>...",
+	Short:             "Delete workspaces",
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              runWorkspaceRm,
+	ValidArgsFunction: completeWorkspaceNames,
+}
+
+var workspaceMvCmd = &cobra.Command{
+	Use:               "mv <name> <new-name>",
+	Short:             "Rename a workspace",
+	Args:              cobra.ExactArgs(2),
+	RunE:              runWorkspaceMv,
+	ValidArgsFunction: completeFirstWorkspace,
+}
+
+var workspaceCpCmd = &cobra.Command{
+	Use:               "cp <name> <new-name>",
+	Short:             "Copy a workspace, converting between YAML and JSON",
+	Args:              cobra.ExactArgs(2),
+	RunE:              runWorkspaceCp,
+	ValidArgsFunction: completeFirstWorkspace,
+}
+
+var (
+	workspaceYes   bool
+	workspaceForce bool
+)
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceShowCmd, workspacePathCmd, workspaceRmCmd, workspaceMvCmd, workspaceCpCmd)
+
+	workspaceRmCmd.Flags().BoolVarP(&workspaceYes, "yes", "y", false, "Delete without asking")
+	workspaceMvCmd.Flags().BoolVarP(&workspaceForce, "force", "f", false, "Overwrite an existing workspace")
+	workspaceCpCmd.Flags().BoolVarP(&workspaceForce, "force", "f", false, "Overwrite an existing workspace")
+}
+
+func runWorkspaceShow(cmd *cobra.Command, args []string) error {
+	path, err := namedWorkspacePath(args[0])
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading workspace: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func runWorkspacePath(cmd *cobra.Command, args []string) error {
+	path, err := namedWorkspacePath(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(path)
+	return nil
+}
+
+func runWorkspaceRm(cmd *cobra.Command, args []string) error {
+	paths := make([]string, len(args))
+	for i, name := range args {
+		path, err := namedWorkspacePath(name)
+		if err != nil {
+			return err
+		}
+		paths[i] = path
+	}
+
+	for i, path := range paths {
+		if !workspaceYes && !confirm(fmt.Sprintf("Delete workspace %s (%s)?", args[i], path), false) {
+			logger.Info("Kept %s", args[i])
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("deleting workspace: %w", err)
+		}
+		if err := os.RemoveAll(scrollbackDir(path)); err != nil {
+			return fmt.Errorf("deleting scrollback: %w", err)
+		}
+		logger.Success("Deleted %s", args[i])
+	}
+	return nil
+}
+
+func runWorkspaceMv(cmd *cobra.Command, args []string) error {
+	src, dst, err := workspaceSourceAndDest(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(src) == filepath.Ext(dst) {
+		err = os.Rename(src, dst)
+	} else if err = copyWorkspace(src, dst); err == nil {
+		err = os.Remove(src)
+	}
+	if err != nil {
+		return fmt.Errorf("renaming workspace: %w", err)
+	}
+	if fileExists(scrollbackDir(src)) {
+		// Scrollback of an overwritten workspace belongs to panes that no
+		// longer exist.
+		if err := os.RemoveAll(scrollbackDir(dst)); err != nil {
+			return fmt.Errorf("renaming scrollback: %w", err)
+		}
+		if err := os.Rename(scrollbackDir(src), scrollbackDir(dst)); err != nil {
+			return fmt.Errorf("renaming scrollback: %w", err)
+		}
+	}
+
+	logger.Success("Renamed %s to %s", args[0], workspaceName(dst))
+	return nil
+}
+
+func runWorkspaceCp(cmd *cobra.Command, args []string) error {
+	src, dst, err := workspaceSourceAndDest(args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := copyWorkspace(src, dst); err != nil {
+		return fmt.Errorf("copying workspace: %w", err)
+	}
+
+	logger.Success("Copied %s to %s", args[0], dst)
+	return nil
+}
+
+// copyWorkspace copies src to a temporary file next to dst and renames it
+// over dst, so a workspace being overwritten is kept if the copy fails.
+func copyWorkspace(src, dst string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+workspaceName(dst)+"-*"+filepath.Ext(dst))
+	if err != nil {
+		return err
+	}
+	err = tmp.Chmod(0644)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := manifest.Copy(src, tmp.Name()); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// workspaceSourceAndDest resolves the workspace to copy or rename and the
+// file it goes to, next to the source. The new name keeps the source format
+// unless it has an extension of its own.
+func workspaceSourceAndDest(name, newName string) (string, string, error) {
+	src, err := namedWorkspacePath(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	if strings.ContainsRune(newName, filepath.Separator) {
+		return "", "", fmt.Errorf("invalid workspace name %q\nHint: Give a name, not a path; the workspace stays in %s", newName, filepath.Dir(src))
+	}
+	ext := filepath.Ext(newName)
+	switch ext {
+	case ".yaml", ".yml", ".json":
+		newName = strings.TrimSuffix(newName, ext)
+	default:
+		ext = filepath.Ext(src)
+	}
+	dst := filepath.Join(filepath.Dir(src), newName+ext)
+
+	if dst == src {
+		return "", "", fmt.Errorf("%s and %s are the same workspace", name, newName)
+	}
+
+	paths, err := workspacePaths()
+	if err != nil {
+		return "", "", err
+	}
+	if existing, ok := paths[newName]; ok && existing != src {
+		if !workspaceForce {
+			return "", "", fmt.Errorf("workspace already exists: %s (%s)\nHint: Use --force to overwrite it", newName, existing)
+		}
+		// --force only replaces the file being written; another format or
+		// directory would leave two workspaces of the same name.
+		if existing != dst {
+			hint := fmt.Sprintf("Remove it first with 'hetki workspace rm %s'", newName)
+			if filepath.Dir(existing) == filepath.Dir(dst) {
+				hint = fmt.Sprintf("Use %s as the new name to overwrite it, or remove it first with 'hetki workspace rm %s'", filepath.Base(existing), newName)
+			}
+			return "", "", fmt.Errorf("workspace already exists: %s (%s)\nHint: %s", newName, existing, hint)
+		}
+	}
+
+	return src, dst, nil
+}
+
+func namedWorkspacePath(name string) (string, error) {
+	paths, err := workspacePaths()
+	if err != nil {
+		return "", err
+	}
+	path, ok := paths[name]
+	if !ok {
+		return "", fmt.Errorf("named workspace not found: %s\nHint: List available workspaces with 'hetki list'", name)
+	}
+	return path, nil
+}
+
+func workspaceName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func completeFirstWorkspace(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeWorkspaceNames(cmd, args, toComplete)
+}
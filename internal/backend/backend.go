@@ -1,5 +1,10 @@
 package backend
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Action interface {
 	Comment() string
 	Validate() error
@@ -8,8 +13,31 @@ type Action interface {
 type Backend interface {
 	Name() string
 	QueryState() (StateResult, error)
+	CapturePane(id string) (string, error)
 	Apply(actions []Action) error
 	DryRun(actions []Action) []string
 	Attach(session string) error
-	Switch(target string) error
+	Switch(target Target) error
+}
+
+// ApplyError is returned by Apply when the backend can tell which action
+// failed. Completed actions took effect; skipped ones were never run.
+type ApplyError struct {
+	Failed    Action
+	Completed []Action
+	Skipped   []Action
+	Err       error
+}
+
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("%s: %v", Describe(e.Failed), e.Err)
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// Describe returns the action comment without its leading "# ".
+func Describe(a Action) string {
+	return strings.TrimPrefix(a.Comment(), "# ")
 }
@@ -0,0 +1,231 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Target addresses a session, a window in it or a pane in that window.
+// Sessions and windows are given by name or, for windows, by index; panes
+// by their position in the window, counting from 0.
+type Target struct {
+	Session string
+	Window  string
+	// WindowIndex, when set, selects the window by index only, for callers
+	// that already know which window they mean.
+	WindowIndex *int
+	Pane        *int
+	// Exact turns off prefix and fuzzy matching, for targets written down
+	// ahead of time that must not come to mean another session or window
+	// once more are running.
+	Exact bool
+
+	// dotted is set when the pane came from a "window.pane" suffix, which
+	// could also be part of the window name.
+	dotted bool
+}
+
+// ParseTarget reads a target written as session, session:window or
+// session:window:pane with delimiter between the parts, the way 'hetki
+// list' prints them. The tmux form session:window.pane is accepted too.
+func ParseTarget(s, delimiter string) (Target, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Target{}, errors.New("empty target")
+	}
+	if delimiter == "" {
+		delimiter = ":"
+	}
+
+	var t Target
+	var pane string
+	parts := strings.Split(s, delimiter)
+	switch len(parts) {
+	case 1:
+		t.Session = parts[0]
+	case 2:
+		t.Session, t.Window = parts[0], parts[1]
+		if i := strings.LastIndex(t.Window, "."); i >= 0 && isNumber(t.Window[i+1:]) {
+			t.Window, pane, t.dotted = t.Window[:i], t.Window[i+1:], true
+		}
+	case 3:
+		t.Session, t.Window, pane = parts[0], parts[1], parts[2]
+	default:
+		return Target{}, fmt.Errorf("invalid target %q\nExample: session%[2]swindow%[2]spane", s, delimiter)
+	}
+
+	if t.Session == "" {
+		return Target{}, fmt.Errorf("invalid target %q: missing session", s)
+	}
+	if pane != "" {
+		n, err := strconv.Atoi(pane)
+		if err != nil || n < 0 {
+			return Target{}, fmt.Errorf("invalid target %q: pane must be a number", s)
+		}
+		t.Pane = &n
+	}
+	return t, nil
+}
+
+func (t Target) String() string {
+	s := t.Session
+	if t.WindowIndex != nil {
+		s += fmt.Sprintf(":%d", *t.WindowIndex)
+	} else if t.Window != "" {
+		s += ":" + t.Window
+	}
+	if t.Pane != nil {
+		s += fmt.Sprintf(".%d", *t.Pane)
+	}
+	return s
+}
+
+// ResolvedTarget is a target matched against running sessions.
+type ResolvedTarget struct {
+	Session string
+	// Window is nil when the target is a whole session.
+	Window *Window
+	// Pane is -1 when the target is a whole window.
+	Pane int
+}
+
+// Resolve finds what t refers to among sessions. Each part is matched by
+// exact name first, then windows by index, then, unless t is Exact, by a
+// unique prefix and finally by a unique fuzzy match, where the characters
+// appear in order. A part matching several names is an error listing them.
+func (t Target) Resolve(sessions []Session) (ResolvedTarget, error) {
+	sessionNames := make([]string, len(sessions))
+	for i, s := range sessions {
+		sessionNames[i] = s.Name
+	}
+	si, err := match("session", "", t.Session, sessionNames, nil, t.Exact)
+	if err != nil {
+		return ResolvedTarget{}, err
+	}
+	sess := sessions[si]
+	resolved := ResolvedTarget{Session: sess.Name, Pane: -1}
+	if t.WindowIndex != nil {
+		i := slices.IndexFunc(sess.Windows, func(w Window) bool { return w.Index == *t.WindowIndex })
+		if i < 0 {
+			return ResolvedTarget{}, fmt.Errorf("no window with index %d in session %q", *t.WindowIndex, sess.Name)
+		}
+		resolved.Window = &sess.Windows[i]
+		return t.resolvePane(resolved)
+	}
+	if t.Window == "" {
+		return resolved, nil
+	}
+
+	windowNames := make([]string, len(sess.Windows))
+	windowIndexes := make([]int, len(sess.Windows))
+	for i, w := range sess.Windows {
+		windowNames[i] = w.Name
+		windowIndexes[i] = w.Index
+	}
+
+	// A window named like "v1.2" wins over window "v1", pane 2.
+	if t.dotted {
+		full := fmt.Sprintf("%s.%d", t.Window, *t.Pane)
+		for i, name := range windowNames {
+			if name == full {
+				resolved.Window = &sess.Windows[i]
+				return resolved, nil
+			}
+		}
+	}
+
+	wi, err := match("window", fmt.Sprintf(" in session %q", sess.Name), t.Window, windowNames, windowIndexes, t.Exact)
+	if err != nil {
+		return ResolvedTarget{}, err
+	}
+	resolved.Window = &sess.Windows[wi]
+	return t.resolvePane(resolved)
+}
+
+func (t Target) resolvePane(resolved ResolvedTarget) (ResolvedTarget, error) {
+	if t.Pane == nil {
+		return resolved, nil
+	}
+	if *t.Pane >= len(resolved.Window.Panes) {
+		return ResolvedTarget{}, fmt.Errorf("window %s:%s has no pane %d (panes count from 0, it has %d)", resolved.Session, resolved.Window.Name, *t.Pane, len(resolved.Window.Panes))
+	}
+	resolved.Pane = *t.Pane
+	return resolved, nil
+}
+
+// match returns the position in names of the one name that query refers
+// to. indexes, when given, lets a number select by index. exact leaves out
+// prefix and fuzzy matches. where is added to errors after the query.
+func match(kind, where, query string, names []string, indexes []int, exact bool) (int, error) {
+	for i, name := range names {
+		if name == query {
+			return i, nil
+		}
+	}
+
+	if n, err := strconv.Atoi(query); err == nil {
+		for i, index := range indexes {
+			if index == n {
+				return i, nil
+			}
+		}
+	}
+
+	var loose []func(string) bool
+	if !exact {
+		loose = []func(string) bool{
+			func(name string) bool { return strings.HasPrefix(name, query) },
+			func(name string) bool { return fuzzyMatch(query, name) },
+		}
+	}
+	for _, matches := range loose {
+		var found []int
+		for i, name := range names {
+			if matches(name) {
+				found = append(found, i)
+			}
+		}
+		switch len(found) {
+		case 0:
+			continue
+		case 1:
+			return found[0], nil
+		default:
+			candidates := make([]string, len(found))
+			for i, f := range found {
+				candidates[i] = names[f]
+			}
+			return 0, fmt.Errorf("%s %q%s is ambiguous, it matches: %s", kind, query, where, strings.Join(candidates, ", "))
+		}
+	}
+
+	if len(names) == 0 {
+		return 0, fmt.Errorf("%s %q not found%s", kind, query, where)
+	}
+	return 0, fmt.Errorf("%s %q not found%s\nAvailable %ss: %s", kind, query, where, kind, strings.Join(names, ", "))
+}
+
+// fuzzyMatch reports whether the characters of query appear in name in
+// order, ignoring case.
+func fuzzyMatch(query, name string) bool {
+	rest := []rune(strings.ToLower(name))
+	for _, r := range strings.ToLower(query) {
+		i := 0
+		for i < len(rest) && rest[i] != r {
+			i++
+		}
+		if i == len(rest) {
+			return false
+		}
+		rest = rest[i+1:]
+	}
+	return true
+}
+
+func isNumber(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
@@ -0,0 +1,183 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		input     string
+		delimiter string
+		expected  Target
+		wantErr   string
+	}{
+		{input: "dev", expected: Target{Session: "dev"}},
+		{input: " dev:editor \n", expected: Target{Session: "dev", Window: "editor"}},
+		{input: "dev:editor:1", expected: Target{Session: "dev", Window: "editor", Pane: intPtr(1)}},
+		{input: "dev:editor.1", expected: Target{Session: "dev", Window: "editor", Pane: intPtr(1), dotted: true}},
+		{input: "dev:v1.x", expected: Target{Session: "dev", Window: "v1.x"}},
+		{input: "dev:2", expected: Target{Session: "dev", Window: "2"}},
+		{input: "dev/editor/0", delimiter: "/", expected: Target{Session: "dev", Window: "editor", Pane: intPtr(0)}},
+		{input: "dev/a:b", delimiter: "/", expected: Target{Session: "dev", Window: "a:b"}},
+		{input: "dev | editor | 1", delimiter: " | ", expected: Target{Session: "dev", Window: "editor", Pane: intPtr(1)}},
+		{input: "", wantErr: "empty target"},
+		{input: ":editor", wantErr: "missing session"},
+		{input: "dev:editor:x", wantErr: "pane must be a number"},
+		{input: "a:b:c:d", wantErr: "invalid target"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTarget(tt.input, tt.delimiter)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestTargetString(t *testing.T) {
+	assert.Equal(t, "dev", Target{Session: "dev"}.String())
+	assert.Equal(t, "dev:3.1", Target{Session: "dev", WindowIndex: intPtr(3), Pane: intPtr(1)}.String())
+	assert.Equal(t, "dev:editor", Target{Session: "dev", Window: "editor"}.String())
+	assert.Equal(t, "dev:editor.1", Target{Session: "dev", Window: "editor", Pane: intPtr(1)}.String())
+}
+
+func resolveSessions() []Session {
+	return []Session{
+		{Name: "api", Windows: []Window{
+			{Name: "editor", Index: 1, Panes: []Pane{{}}},
+			{Name: "server", Index: 2, Panes: []Pane{{}, {}}},
+			{Name: "v1", Index: 3, Panes: []Pane{{}, {}, {}}},
+			{Name: "v1.2", Index: 4, Panes: []Pane{{}}},
+		}},
+		{Name: "api-docs", Windows: []Window{{Name: "shell", Index: 1, Panes: []Pane{{}}}}},
+		{Name: "web", Windows: []Window{
+			{Name: "editor", Index: 1, Panes: []Pane{{}}},
+			{Name: "logs", Index: 2, Panes: []Pane{{}}},
+			{Name: "lint", Index: 3, Panes: []Pane{{}}},
+		}},
+	}
+}
+
+func TestTargetResolve(t *testing.T) {
+	tests := []struct {
+		input   string
+		session string
+		window  string
+		pane    int
+		wantErr string
+	}{
+		{input: "api", session: "api", pane: -1},
+		{input: "we", session: "web", pane: -1},
+		{input: "ad", session: "api-docs", pane: -1},
+		{input: "api:server", session: "api", window: "server", pane: -1},
+		{input: "api:2", session: "api", window: "server", pane: -1},
+		{input: "api:se", session: "api", window: "server", pane: -1},
+		{input: "api:srv", session: "api", window: "server", pane: -1},
+		{input: "api:server:1", session: "api", window: "server", pane: 1},
+		{input: "api:server.1", session: "api", window: "server", pane: 1},
+		{input: "api:v1.2", session: "api", window: "v1.2", pane: -1},
+		{input: "api:v1.1", session: "api", window: "v1", pane: 1},
+		{input: "api:v1:2", session: "api", window: "v1", pane: 2},
+		{input: "web:lo", session: "web", window: "logs", pane: -1},
+		{input: "a", wantErr: `session "a" is ambiguous, it matches: api, api-docs`},
+		{input: "db", wantErr: "Available sessions: api, api-docs, web"},
+		{input: "web:l", wantErr: `window "l" in session "web" is ambiguous, it matches: logs, lint`},
+		{input: "web:9", wantErr: "window \"9\" not found in session \"web\"\nAvailable windows: editor, logs, lint"},
+		{input: "api:server:2", wantErr: "has no pane 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			target, err := ParseTarget(tt.input, ":")
+			require.NoError(t, err)
+
+			got, err := target.Resolve(resolveSessions())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.session, got.Session)
+			if tt.window == "" {
+				assert.Nil(t, got.Window)
+			} else {
+				require.NotNil(t, got.Window)
+				assert.Equal(t, tt.window, got.Window.Name)
+			}
+			assert.Equal(t, tt.pane, got.Pane)
+		})
+	}
+}
+
+func TestTargetResolveExact(t *testing.T) {
+	tests := []struct {
+		input   string
+		session string
+		window  string
+		wantErr string
+	}{
+		{input: "api", session: "api"},
+		{input: "api:server", session: "api", window: "server"},
+		{input: "api:2", session: "api", window: "server"},
+		{input: "we", wantErr: `session "we" not found`},
+		{input: "api:srv", wantErr: `window "srv" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			target, err := ParseTarget(tt.input, ":")
+			require.NoError(t, err)
+			target.Exact = true
+
+			got, err := target.Resolve(resolveSessions())
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.session, got.Session)
+			if tt.window != "" {
+				require.NotNil(t, got.Window)
+				assert.Equal(t, tt.window, got.Window.Name)
+			}
+		})
+	}
+}
+
+func TestTargetResolveWindowIndex(t *testing.T) {
+	sessions := []Session{{Name: "dev", Windows: []Window{
+		{Name: "main", Index: 1, Panes: []Pane{{}}},
+		{Name: "2", Index: 2, Panes: []Pane{{}}},
+		{Name: "main", Index: 3, Panes: []Pane{{}, {}}},
+	}}}
+
+	got, err := Target{Session: "dev", WindowIndex: intPtr(3), Pane: intPtr(1)}.Resolve(sessions)
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.Window.Index)
+	assert.Equal(t, 1, got.Pane)
+
+	// By name the first "main" wins.
+	got, err = Target{Session: "dev", Window: "main"}.Resolve(sessions)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Window.Index)
+
+	got, err = Target{Session: "dev", WindowIndex: intPtr(2)}.Resolve(sessions)
+	require.NoError(t, err)
+	assert.Equal(t, "2", got.Window.Name)
+
+	_, err = Target{Session: "dev", WindowIndex: intPtr(7)}.Resolve(sessions)
+	assert.ErrorContains(t, err, "no window with index 7")
+
+	_, err = Target{Session: "dev", WindowIndex: intPtr(1), Pane: intPtr(1)}.Resolve(sessions)
+	assert.ErrorContains(t, err, "has no pane 1")
+}
@@ -1,5 +1,7 @@
 package tmux
 
+import "fmt"
+
 type Action interface {
 	Args() []string
 }
@@ -21,14 +23,23 @@ func (a CreateSession) Args() []string {
 	return args
 }
 
+// CreateWindow creates a window at a given index, so later actions can
+// target it without relying on its name. A detached window does not become
+// the active one.
 type CreateWindow struct {
-	Session string
-	Name    string
-	Path    string
+	Session  string
+	Index    int
+	Name     string
+	Path     string
+	Detached bool
 }
 
 func (a CreateWindow) Args() []string {
-	args := []string{"new-window", "-t", a.Session + ":", "-n", a.Name}
+	args := []string{"new-window"}
+	if a.Detached {
+		args = append(args, "-d")
+	}
+	args = append(args, "-t", fmt.Sprintf("%s:%d", a.Session, a.Index), "-n", a.Name)
 	if a.Path != "" {
 		args = append(args, "-c", a.Path)
 	}
@@ -91,6 +102,36 @@ func (a ZoomPane) Args() []string {
 	return []string{"resize-pane", "-Z", "-t", a.Target}
 }
 
+type SelectWindow struct {
+	Target string
+}
+
+func (a SelectWindow) Args() []string {
+	return []string{"select-window", "-t", a.Target}
+}
+
+type SelectPane struct {
+	Target string
+}
+
+func (a SelectPane) Args() []string {
+	return []string{"select-pane", "-t", a.Target}
+}
+
+type RespawnPane struct {
+	Target  string
+	Path    string
+	Command string
+}
+
+func (a RespawnPane) Args() []string {
+	args := []string{"respawn-pane", "-k", "-t", a.Target}
+	if a.Path != "" {
+		args = append(args, "-c", a.Path)
+	}
+	return append(args, a.Command)
+}
+
 type SwitchClient struct {
 	Target string
 }
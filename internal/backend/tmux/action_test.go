@@ -24,8 +24,13 @@ func TestAction_Args(t *testing.T) {
 		},
 		{
 			name:   "create window",
-			action: CreateWindow{Session: "dev", Name: "editor", Path: "~/code"},
-			want:   []string{"new-window", "-t", "dev:", "-n", "editor", "-c", "~/code"},
+			action: CreateWindow{Session: "dev", Index: 2, Name: "editor", Path: "~/code"},
+			want:   []string{"new-window", "-t", "dev:2", "-n", "editor", "-c", "~/code"},
+		},
+		{
+			name:   "create detached window",
+			action: CreateWindow{Session: "dev", Index: 3, Name: "logs", Detached: true},
+			want:   []string{"new-window", "-d", "-t", "dev:3", "-n", "logs"},
 		},
 		{
 			name:   "split pane",
@@ -47,6 +52,16 @@ func TestAction_Args(t *testing.T) {
 			action: KillWindow{Target: "dev:editor"},
 			want:   []string{"kill-window", "-t", "dev:editor"},
 		},
+		{
+			name:   "select window",
+			action: SelectWindow{Target: "dev:editor"},
+			want:   []string{"select-window", "-t", "dev:editor"},
+		},
+		{
+			name:   "select pane",
+			action: SelectPane{Target: "dev:editor.1"},
+			want:   []string{"select-pane", "-t", "dev:editor.1"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -14,6 +14,21 @@ type Client interface {
 	ExecuteBatch(actions []Action) error
 }
 
+// BatchError reports the action of an ExecuteBatch call that failed. Actions
+// before Index were executed, the ones after it were not.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
 type client struct {
 	bin string
 }
@@ -63,9 +78,9 @@ func (c *client) ExecuteBatch(actions []Action) error {
 	}
 
 	err := c.executeSource(actions)
-	if err != nil && c.isServerNotRunning(err) {
+	if err != nil && isServerNotRunning(err) {
 		if err := exec.Command(c.bin, actions[0].Args()...).Run(); err != nil {
-			return fmt.Errorf("failed to start tmux: %w", err)
+			return &BatchError{Index: 0, Err: fmt.Errorf("failed to start tmux: %w", err)}
 		}
 		if len(actions) > 1 {
 			return c.executeSource(actions[1:])
@@ -94,7 +109,7 @@ func (c *client) executeSource(actions []Action) error {
 	return nil
 }
 
-func (c *client) isServerNotRunning(err error) bool {
+func isServerNotRunning(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "no server running")
 }
 
@@ -47,9 +47,9 @@ func TestRunQuery(t *testing.T) {
 	}{
 		{
 			name:   "success",
-			output: "0\n0\n$1|dev|editor|0|1|0|1|~/code|vim",
+			output: "0\n0\n$1|dev|0|0|0|editor|0|1|b25d,80x24,0,0,0|0|0|%0|1|4242|~/code|vim",
 			want: LoadStateResult{
-				Sessions: []Session{{Name: "dev", Windows: []Window{{Name: "editor", Index: 0, Path: "~/code", Panes: []Pane{{Path: "~/code", Command: "vim"}}}}}},
+				Sessions: []Session{{Name: "dev", Windows: []Window{{Name: "editor", Index: 0, Path: "~/code", Layout: "b25d,80x24,0,0,0", Active: true, Panes: []Pane{{ID: "%0", Path: "~/code", Command: "vim", PID: 4242, Active: true}}}}}},
 			},
 		},
 		{
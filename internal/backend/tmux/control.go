@@ -0,0 +1,344 @@
+package tmux
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/MSmaili/hetki/internal/proc"
+)
+
+const controlReadyToken = "hetki-control-ready"
+
+// controlSessionPrefix names the private session the control connection
+// attaches to, followed by the pid of its hetki process. Queries leave these
+// sessions out.
+const controlSessionPrefix = "_hetki-control-"
+
+// controlClient sends the commands of each Run and ExecuteBatch call over one
+// `tmux -C` connection, opened for the call and closed when it returns. The
+// connection attaches to a private session of its own, so the user's
+// sessions are never attached by it and keep their activity times. That
+// session is visible to every tmux client (tmux ls, choose-tree) and keeps
+// the server running while it exists, which is why connections do not
+// outlive a call. It uses ignore-size and no-output so it does not affect
+// window sizes or receive pane output. Interactive commands (attach, switch)
+// still go through the spawning client since they act on the user's
+// terminal.
+type controlClient struct {
+	exec *client
+
+	mu      sync.Mutex
+	proc    *exec.Cmd
+	session string
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+}
+
+// NewControl returns a Client backed by tmux control-mode connections. While
+// no server is running, queries fall back to spawning tmux and batches
+// bootstrap the server with their first action.
+func NewControl() (Client, error) {
+	bin, err := exec.LookPath("tmux")
+	if err != nil {
+		return nil, fmt.Errorf("tmux not found in PATH")
+	}
+	return &controlClient{exec: &client{bin: bin}}, nil
+}
+
+// CommandError reports a tmux command that answered with an %error block.
+type CommandError struct {
+	Args    []string
+	Message string
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("tmux %s: %s", strings.Join(e.Args, " "), e.Message)
+}
+
+func (c *controlClient) Run(args ...string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(); err != nil {
+		if isServerNotRunning(err) {
+			return c.exec.Run(args...)
+		}
+		return "", err
+	}
+	defer c.disconnect()
+
+	var outputs []string
+	for _, cmdArgs := range splitCommands(args) {
+		out, err := c.send(cmdArgs)
+		if out != "" {
+			outputs = append(outputs, out)
+		}
+		if err != nil {
+			return strings.TrimSpace(strings.Join(outputs, "\n")), err
+		}
+	}
+	return strings.TrimSpace(strings.Join(outputs, "\n")), nil
+}
+
+func (c *controlClient) Execute(action Action) error {
+	return c.exec.Execute(action)
+}
+
+func (c *controlClient) ExecuteBatch(actions []Action) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.disconnect()
+
+	retried := false
+	for i := 0; i < len(actions); {
+		if err := c.connect(); err != nil {
+			if !isServerNotRunning(err) {
+				return &BatchError{Index: i, Err: err}
+			}
+			if err := exec.Command(c.exec.bin, actions[i].Args()...).Run(); err != nil {
+				return &BatchError{Index: i, Err: fmt.Errorf("failed to start tmux: %w", err)}
+			}
+			i++
+			continue
+		}
+
+		_, err := c.send(actions[i].Args())
+		if errors.Is(err, errControlExit) && !retried {
+			// The connection went away, e.g. after killing the server.
+			retried = true
+			continue
+		}
+		if err != nil {
+			return &BatchError{Index: i, Err: err}
+		}
+		retried = false
+		i++
+	}
+	return nil
+}
+
+// Close ends the control-mode session if a call left one open. It is safe to
+// call on a client that never connected.
+func (c *controlClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.disconnect()
+}
+
+func (c *controlClient) connect() error {
+	if c.proc != nil {
+		return nil
+	}
+
+	// -N keeps a query from starting a server just to find it empty.
+	name := fmt.Sprintf("%s%d", controlSessionPrefix, os.Getpid())
+	proc := exec.Command(c.exec.bin, "-N", "-C", "new-session", "-s", name, "-f", "ignore-size,no-output", "cat")
+	stdin, err := proc.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := proc.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	proc.Stderr = &stderr
+
+	if err := proc.Start(); err != nil {
+		return fmt.Errorf("starting tmux control mode: %w", err)
+	}
+
+	c.proc, c.session, c.stdin, c.stdout = proc, name, stdin, bufio.NewReader(stdout)
+
+	if err := c.sync(); err != nil {
+		c.disconnect()
+		s := strings.TrimSpace(stderr.String())
+		// -N fails to connect, or the server exits just as it connects.
+		if strings.HasPrefix(s, "error connecting to") || s == "" && errors.Is(err, errControlExit) {
+			return fmt.Errorf("tmux control mode: no server running")
+		}
+		if s != "" {
+			return fmt.Errorf("tmux control mode: %s", s)
+		}
+		return fmt.Errorf("tmux control mode: %w", err)
+	}
+	c.removeStaleSessions()
+	return nil
+}
+
+// removeStaleSessions kills control sessions left behind by hetki processes
+// that ended without disconnecting.
+func (c *controlClient) removeStaleSessions() {
+	out, err := c.send([]string{"list-sessions", "-F", "#{session_name}"})
+	if err != nil {
+		return
+	}
+	for _, name := range strings.Split(out, "\n") {
+		pid, ok := strings.CutPrefix(name, controlSessionPrefix)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(pid); err != nil || processRunning(n) {
+			continue
+		}
+		c.send([]string{"kill-session", "-t", "=" + name})
+	}
+}
+
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// sync discards anything tmux emits on startup until the reply to a marker
+// command arrives, so every later block belongs to a command we sent. tmux
+// may answer it before the session from the command line exists, so sync
+// also waits for the client to be attached to that session.
+func (c *controlClient) sync() error {
+	if _, err := fmt.Fprintf(c.stdin, "display-message -p %s\n", controlReadyToken); err != nil {
+		return err
+	}
+	ready, attached := false, false
+	for !ready || !attached {
+		line, err := c.stdout.ReadString('\n')
+		if err == io.EOF {
+			return errControlExit
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == controlReadyToken:
+			ready = true
+		case strings.HasPrefix(line, "%session-changed ") && strings.HasSuffix(line, " "+c.session):
+			attached = true
+		case strings.HasPrefix(line, "%exit"):
+			return errControlExit
+		}
+	}
+	return nil
+}
+
+func (c *controlClient) disconnect() error {
+	if c.proc == nil {
+		return nil
+	}
+	// Killing the session it is attached to ends the connection. The
+	// session is not left to destroy-unattached, which can take the tmux
+	// 3.3 server down with it. It is named explicitly, since without a
+	// session of its own the client would kill the user's most recent
+	// one, and the reply is awaited before stdin is closed.
+	io.WriteString(c.stdin, proc.ShellJoin([]string{"kill-session", "-t", "=" + c.session})+"\n")
+	readBlock(c.stdout)
+	c.stdin.Close()
+	io.Copy(io.Discard, c.stdout)
+	err := c.proc.Wait()
+	c.proc, c.session, c.stdin, c.stdout = nil, "", nil, nil
+	return err
+}
+
+func (c *controlClient) send(args []string) (string, error) {
+	if _, err := io.WriteString(c.stdin, proc.ShellJoin(args)+"\n"); err != nil {
+		// A closed pipe means tmux already ended the connection.
+		c.disconnect()
+		return "", errControlExit
+	}
+
+	block, err := readBlock(c.stdout)
+	if err != nil {
+		c.disconnect()
+		return "", err
+	}
+
+	if block.Failed {
+		return "", &CommandError{Args: args, Message: strings.TrimSpace(block.Output)}
+	}
+	return block.Output, nil
+}
+
+type controlBlock struct {
+	Output string
+	Failed bool
+}
+
+var errControlExit = errors.New("tmux control mode exited")
+
+// readBlock returns the next %begin/%end or %begin/%error block of a command
+// sent by this client, skipping notifications such as %sessions-changed and
+// blocks tmux emits on its own (flags 0), like the reply to the initial attach.
+func readBlock(r *bufio.Reader) (controlBlock, error) {
+	var (
+		guard   string
+		inBlock bool
+		lines   []string
+	)
+
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			return controlBlock{}, errControlExit
+		}
+		if err != nil {
+			return controlBlock{}, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		if !inBlock {
+			if rest, ok := strings.CutPrefix(line, "%begin "); ok {
+				guard, inBlock, lines = rest, true, nil
+			} else if strings.HasPrefix(line, "%exit") {
+				return controlBlock{}, errControlExit
+			}
+			continue
+		}
+
+		end, isEnd := strings.CutPrefix(line, "%end ")
+		failed, isError := strings.CutPrefix(line, "%error ")
+		if (isEnd && end == guard) || (isError && failed == guard) {
+			if strings.HasSuffix(guard, " 0") {
+				inBlock = false
+				continue
+			}
+			return controlBlock{Output: strings.Join(lines, "\n"), Failed: isError}, nil
+		}
+		lines = append(lines, line)
+	}
+}
+
+// splitCommands breaks a `a ; b` argument list into separate commands so
+// each one gets its own reply block.
+func splitCommands(args []string) [][]string {
+	var (
+		cmds    [][]string
+		current []string
+	)
+	for _, arg := range args {
+		if arg == ";" {
+			if len(current) > 0 {
+				cmds = append(cmds, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, arg)
+	}
+	if len(current) > 0 {
+		cmds = append(cmds, current)
+	}
+	return cmds
+}
@@ -0,0 +1,142 @@
+package tmux
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/MSmaili/hetki/internal/proc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    controlBlock
+		wantErr error
+	}{
+		{
+			name:  "output block",
+			input: "%begin 1 10 1\nfoo\nbar\n%end 1 10 1\n",
+			want:  controlBlock{Output: "foo\nbar"},
+		},
+		{
+			name:  "empty block",
+			input: "%begin 1 10 1\n%end 1 10 1\n",
+			want:  controlBlock{},
+		},
+		{
+			name:  "error block",
+			input: "%begin 1 11 1\ncan't find session: nope\n%error 1 11 1\n",
+			want:  controlBlock{Output: "can't find session: nope", Failed: true},
+		},
+		{
+			name:  "skips notifications",
+			input: "%sessions-changed\n%window-add @1\n%begin 1 12 1\nok\n%end 1 12 1\n",
+			want:  controlBlock{Output: "ok"},
+		},
+		{
+			name:  "skips blocks not sent by this client",
+			input: "%begin 1 9 0\n%end 1 9 0\n%begin 1 10 1\nok\n%end 1 10 1\n",
+			want:  controlBlock{Output: "ok"},
+		},
+		{
+			name:  "ignores end lines of other blocks",
+			input: "%begin 1 13 1\n%end 1 99 1\n%end 1 13 1\n",
+			want:  controlBlock{Output: "%end 1 99 1"},
+		},
+		{
+			name:    "exit",
+			input:   "%exit\n",
+			wantErr: errControlExit,
+		},
+		{
+			name:    "eof",
+			input:   "%begin 1 14 1\npartial\n",
+			wantErr: errControlExit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readBlock(bufio.NewReader(strings.NewReader(tt.input)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitCommands(t *testing.T) {
+	got := splitCommands([]string{"start-server", ";", "show-options", "-gv", "base-index", ";"})
+	assert.Equal(t, [][]string{{"start-server"}, {"show-options", "-gv", "base-index"}}, got)
+}
+
+// Control-mode lines go through the tmux command parser, which accepts
+// the shell quoting of proc.ShellJoin.
+func TestControlLine(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"plain", []string{"new-session", "-d", "-s", "dev"}, "new-session -d -s dev"},
+		{"target", []string{"split-window", "-t", "dev:1.0", "-c", "/home/user/code"}, "split-window -t dev:1.0 -c /home/user/code"},
+		{"spaces", []string{"send-keys", "-t", "dev:1", "npm run dev", "Enter"}, "send-keys -t dev:1 'npm run dev' Enter"},
+		{"single quote", []string{"send-keys", "echo 'hi'"}, `send-keys 'echo '\''hi'\'''`},
+		{"format", []string{"-F", "#{session_name}|#{window_name}"}, "-F '#{session_name}|#{window_name}'"},
+		{"empty", []string{"-c", ""}, "-c ''"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, proc.ShellJoin(tt.args))
+		})
+	}
+}
+
+func TestControlQueryLeavesSessionsDetached(t *testing.T) {
+	bin, err := exec.LookPath("tmux")
+	if err != nil {
+		t.Skip("tmux not installed")
+	}
+	t.Setenv("TMUX", "")
+	os.Unsetenv("TMUX")
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+
+	tmux := func(args ...string) string {
+		out, err := exec.Command(bin, args...).Output()
+		require.NoError(t, err)
+		return strings.TrimSpace(string(out))
+	}
+	// A command of its own keeps the session alive whatever the shell does.
+	tmux("new-session", "-d", "-s", "dev", "cat")
+	t.Cleanup(func() { exec.Command(bin, "kill-server").Run() })
+
+	// A control session of a hetki process that is gone.
+	ended := exec.Command("true")
+	require.NoError(t, ended.Run())
+	tmux("new-session", "-d", "-s", fmt.Sprintf("%s%d", controlSessionPrefix, ended.Process.Pid), "cat")
+
+	before := tmux("display-message", "-p", "-t", "=dev", "#{session_attached} #{session_activity}")
+
+	c := &controlClient{exec: &client{bin: bin}}
+	state, err := RunQuery[LoadStateResult](c, LoadStateQuery{})
+	require.NoError(t, err)
+	require.Len(t, state.Sessions, 1)
+	assert.Equal(t, "dev", state.Sessions[0].Name)
+	assert.False(t, state.Sessions[0].Attached)
+	assert.Equal(t, before, tmux("display-message", "-p", "-t", "=dev", "#{session_attached} #{session_activity}"))
+
+	// The connection ends with the query, taking its own session along.
+	assert.Equal(t, "dev", tmux("list-sessions", "-F", "#{session_name}"))
+	assert.NoError(t, c.Close())
+}
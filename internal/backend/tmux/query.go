@@ -3,7 +3,10 @@ package tmux
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Query[T any] interface {
@@ -22,8 +25,11 @@ func RunQuery[T any](c Client, q Query[T]) (T, error) {
 }
 
 type Session struct {
-	Name    string
-	Windows []Window
+	Name     string
+	Attached bool
+	Created  time.Time
+	Activity time.Time
+	Windows  []Window
 }
 
 type LoadStateResult struct {
@@ -48,7 +54,7 @@ func (q LoadStateQuery) Args() []string {
 		";", "show-options", "-gv", "base-index",
 		";", "show-options", "-gv", "pane-base-index",
 		";", "list-panes", "-a",
-		"-F", "#{session_id}|#{session_name}|#{window_name}|#{window_index}|#{window_active}|#{pane_index}|#{pane_active}|#{pane_current_path}|#{pane_current_command}",
+		"-F", "#{session_id}|#{session_name}|#{session_attached}|#{session_created}|#{session_activity}|#{window_name}|#{window_index}|#{window_active}|#{window_layout}|#{window_zoomed_flag}|#{pane_index}|#{pane_id}|#{pane_active}|#{pane_pid}|#{pane_current_path}|#{pane_current_command}",
 	}
 }
 
@@ -83,10 +89,16 @@ func (q LoadStateQuery) Parse(output string) (LoadStateResult, error) {
 
 type paneLine struct {
 	sessionID, sessionName, windowName string
+	sessionAttached                    bool
+	sessionCreated, sessionActivity    time.Time
 	windowIndex                        int
 	windowActive                       bool
+	windowLayout                       string
+	windowZoomed                       bool
 	paneIndex                          int
+	paneID                             string
 	paneActive                         bool
+	panePID                            int
 	panePath, paneCmd                  string
 }
 
@@ -98,7 +110,8 @@ func parsePaneLine(line string) (paneLine, bool) {
 
 	var p paneLine
 	var ok bool
-	var windowIndexStr, windowActiveStr, paneIndexStr, paneActiveStr string
+	var attachedStr, createdStr, activityStr string
+	var windowIndexStr, windowActiveStr, windowZoomedStr, paneIndexStr, paneActiveStr, panePIDStr string
 
 	if p.sessionID, line, ok = strings.Cut(line, "|"); !ok {
 		return paneLine{}, false
@@ -106,6 +119,18 @@ func parsePaneLine(line string) (paneLine, bool) {
 	if p.sessionName, line, ok = strings.Cut(line, "|"); !ok {
 		return paneLine{}, false
 	}
+	if attachedStr, line, ok = strings.Cut(line, "|"); !ok {
+		return paneLine{}, false
+	}
+	p.sessionAttached = attachedStr != "" && attachedStr != "0"
+	if createdStr, line, ok = strings.Cut(line, "|"); !ok {
+		return paneLine{}, false
+	}
+	p.sessionCreated = parseUnixTime(createdStr)
+	if activityStr, line, ok = strings.Cut(line, "|"); !ok {
+		return paneLine{}, false
+	}
+	p.sessionActivity = parseUnixTime(activityStr)
 	if p.windowName, line, ok = strings.Cut(line, "|"); !ok {
 		return paneLine{}, false
 	}
@@ -118,16 +143,33 @@ func parsePaneLine(line string) (paneLine, bool) {
 	}
 	p.windowActive = windowActiveStr == "1"
 
+	if p.windowLayout, line, ok = strings.Cut(line, "|"); !ok {
+		return paneLine{}, false
+	}
+	if windowZoomedStr, line, ok = strings.Cut(line, "|"); !ok {
+		return paneLine{}, false
+	}
+	p.windowZoomed = windowZoomedStr == "1"
+
 	if paneIndexStr, line, ok = strings.Cut(line, "|"); !ok {
 		return paneLine{}, false
 	}
 	fmt.Sscanf(paneIndexStr, "%d", &p.paneIndex)
 
+	if p.paneID, line, ok = strings.Cut(line, "|"); !ok {
+		return paneLine{}, false
+	}
+
 	if paneActiveStr, line, ok = strings.Cut(line, "|"); !ok {
 		return paneLine{}, false
 	}
 	p.paneActive = paneActiveStr == "1"
 
+	if panePIDStr, line, ok = strings.Cut(line, "|"); !ok {
+		return paneLine{}, false
+	}
+	fmt.Sscanf(panePIDStr, "%d", &p.panePID)
+
 	if p.panePath, line, ok = strings.Cut(line, "|"); !ok {
 		return paneLine{}, false
 	}
@@ -135,6 +177,16 @@ func parsePaneLine(line string) (paneLine, bool) {
 	return p, true
 }
 
+// parseUnixTime reads a tmux time format, seconds since the epoch. Missing
+// or invalid values give the zero time.
+func parseUnixTime(s string) time.Time {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(n, 0)
+}
+
 type stateBuilder struct {
 	sessions map[string]*Session
 	active   ActiveContext
@@ -145,6 +197,9 @@ func newStateBuilder() *stateBuilder {
 }
 
 func (b *stateBuilder) addPane(p paneLine, currentID string) {
+	if strings.HasPrefix(p.sessionName, controlSessionPrefix) {
+		return
+	}
 	if p.sessionID == currentID {
 		b.active.Session = p.sessionName
 		if p.windowActive {
@@ -156,27 +211,39 @@ func (b *stateBuilder) addPane(p paneLine, currentID string) {
 		}
 	}
 
-	sess := b.getOrCreateSession(p.sessionName)
-	win := b.getOrCreateWindow(sess, p.windowName, p.windowIndex, p.panePath)
-	win.Panes = append(win.Panes, Pane{Path: p.panePath, Command: p.paneCmd})
+	sess := b.getOrCreateSession(p)
+	win := b.getOrCreateWindow(sess, p)
+	win.Panes = append(win.Panes, Pane{
+		ID:      p.paneID,
+		Path:    p.panePath,
+		Command: p.paneCmd,
+		PID:     p.panePID,
+		Zoom:    p.windowZoomed && p.paneActive,
+		Active:  p.paneActive,
+	})
 }
 
-func (b *stateBuilder) getOrCreateSession(name string) *Session {
-	if sess, ok := b.sessions[name]; ok {
+func (b *stateBuilder) getOrCreateSession(p paneLine) *Session {
+	if sess, ok := b.sessions[p.sessionName]; ok {
 		return sess
 	}
-	sess := &Session{Name: name}
-	b.sessions[name] = sess
+	sess := &Session{
+		Name:     p.sessionName,
+		Attached: p.sessionAttached,
+		Created:  p.sessionCreated,
+		Activity: p.sessionActivity,
+	}
+	b.sessions[p.sessionName] = sess
 	return sess
 }
 
-func (b *stateBuilder) getOrCreateWindow(sess *Session, name string, index int, path string) *Window {
+func (b *stateBuilder) getOrCreateWindow(sess *Session, p paneLine) *Window {
 	for i := range sess.Windows {
-		if sess.Windows[i].Index == index {
+		if sess.Windows[i].Index == p.windowIndex {
 			return &sess.Windows[i]
 		}
 	}
-	sess.Windows = append(sess.Windows, Window{Name: name, Index: index, Path: path})
+	sess.Windows = append(sess.Windows, Window{Name: p.windowName, Index: p.windowIndex, Path: p.panePath, Layout: p.windowLayout, Active: p.windowActive})
 	return &sess.Windows[len(sess.Windows)-1]
 }
 
@@ -185,6 +252,7 @@ func (b *stateBuilder) result() LoadStateResult {
 	for _, s := range b.sessions {
 		sessions = append(sessions, *s)
 	}
+	slices.SortFunc(sessions, func(a, b Session) int { return strings.Compare(a.Name, b.Name) })
 	return LoadStateResult{Sessions: sessions, Active: b.active}
 }
 
@@ -199,3 +267,16 @@ func getCurrentSessionID() string {
 	}
 	return "$" + parts[2]
 }
+
+// CapturePaneQuery returns the full history and visible contents of a pane.
+type CapturePaneQuery struct {
+	Target string
+}
+
+func (q CapturePaneQuery) Args() []string {
+	return []string{"capture-pane", "-p", "-S", "-", "-t", q.Target}
+}
+
+func (q CapturePaneQuery) Parse(output string) (string, error) {
+	return output, nil
+}
@@ -2,6 +2,7 @@ package tmux
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,7 +16,7 @@ func TestLoadStateQuery(t *testing.T) {
 			";", "show-options", "-gv", "base-index",
 			";", "show-options", "-gv", "pane-base-index",
 			";", "list-panes", "-a", "-F",
-			"#{session_id}|#{session_name}|#{window_name}|#{window_index}|#{window_active}|#{pane_index}|#{pane_active}|#{pane_current_path}|#{pane_current_command}",
+			"#{session_id}|#{session_name}|#{session_attached}|#{session_created}|#{session_activity}|#{window_name}|#{window_index}|#{window_active}|#{window_layout}|#{window_zoomed_flag}|#{pane_index}|#{pane_id}|#{pane_active}|#{pane_pid}|#{pane_current_path}|#{pane_current_command}",
 		}
 		assert.Equal(t, expected, q.Args())
 	})
@@ -30,30 +31,54 @@ func TestLoadStateQuery(t *testing.T) {
 		{"empty", "", LoadStateResult{}},
 		{
 			name:   "single session single window single pane",
-			output: "0\n0\n$1|dev|editor|0|1|0|1|~/code|vim",
+			output: "0\n0\n$1|dev|0|0|0|editor|0|1|b25d,80x24,0,0,0|0|0|%0|1|4242|~/code|vim",
 			want: LoadStateResult{
 				Sessions: []Session{{
 					Name: "dev",
 					Windows: []Window{{
-						Name:  "editor",
-						Index: 0,
-						Path:  "~/code",
-						Panes: []Pane{{Path: "~/code", Command: "vim"}},
+						Name:   "editor",
+						Index:  0,
+						Path:   "~/code",
+						Layout: "b25d,80x24,0,0,0",
+						Active: true,
+						Panes:  []Pane{{ID: "%0", Path: "~/code", Command: "vim", PID: 4242, Active: true}},
+					}},
+				}},
+			},
+		},
+		{
+			name:   "session attached with times",
+			output: "0\n0\n$1|dev|2|1700000000|1700000600|editor|0|1|b25d,80x24,0,0,0|0|0|%0|1|4242|~/code|vim",
+			want: LoadStateResult{
+				Sessions: []Session{{
+					Name:     "dev",
+					Attached: true,
+					Created:  time.Unix(1700000000, 0),
+					Activity: time.Unix(1700000600, 0),
+					Windows: []Window{{
+						Name:   "editor",
+						Index:  0,
+						Path:   "~/code",
+						Layout: "b25d,80x24,0,0,0",
+						Active: true,
+						Panes:  []Pane{{ID: "%0", Path: "~/code", Command: "vim", PID: 4242, Active: true}},
 					}},
 				}},
 			},
 		},
 		{
 			name:   "multiple panes same window",
-			output: "0\n1\n$1|dev|editor|0|1|0|0|~/code|vim\n$1|dev|editor|0|1|1|1|~/api|node",
+			output: "0\n1\n$1|dev|0|0|0|editor|0|1|b25d,80x24,0,0,0|0|0|%0|0|4242|~/code|vim\n$1|dev|0|0|0|editor|0|1|b25d,80x24,0,0,0|0|1|%1|1|4242|~/api|node",
 			want: LoadStateResult{
 				Sessions: []Session{{
 					Name: "dev",
 					Windows: []Window{{
-						Name:  "editor",
-						Index: 0,
-						Path:  "~/code",
-						Panes: []Pane{{Path: "~/code", Command: "vim"}, {Path: "~/api", Command: "node"}},
+						Name:   "editor",
+						Index:  0,
+						Path:   "~/code",
+						Layout: "b25d,80x24,0,0,0",
+						Active: true,
+						Panes:  []Pane{{ID: "%0", Path: "~/code", Command: "vim", PID: 4242}, {ID: "%1", Path: "~/api", Command: "node", PID: 4242, Active: true}},
 					}},
 				}},
 				PaneBaseIndex: 1,
@@ -61,19 +86,49 @@ func TestLoadStateQuery(t *testing.T) {
 		},
 		{
 			name:   "multiple windows",
-			output: "1\n1\n$1|dev|editor|0|0|0|0|~/code|vim\n$1|dev|server|1|1|0|1|~/api|node",
+			output: "1\n1\n$1|dev|0|0|0|editor|0|0|b25d,80x24,0,0,0|0|0|%0|0|4242|~/code|vim\n$1|dev|0|0|0|server|1|1|b25d,80x24,0,0,0|0|0|%1|1|4242|~/api|node",
 			want: LoadStateResult{
 				Sessions: []Session{{
 					Name: "dev",
 					Windows: []Window{
-						{Name: "editor", Index: 0, Path: "~/code", Panes: []Pane{{Path: "~/code", Command: "vim"}}},
-						{Name: "server", Index: 1, Path: "~/api", Panes: []Pane{{Path: "~/api", Command: "node"}}},
+						{Name: "editor", Index: 0, Path: "~/code", Layout: "b25d,80x24,0,0,0", Panes: []Pane{{ID: "%0", Path: "~/code", Command: "vim", PID: 4242}}},
+						{Name: "server", Index: 1, Path: "~/api", Layout: "b25d,80x24,0,0,0", Active: true, Panes: []Pane{{ID: "%1", Path: "~/api", Command: "node", PID: 4242, Active: true}}},
 					},
 				}},
 				WindowBaseIndex: 1,
 				PaneBaseIndex:   1,
 			},
 		},
+		{
+			name:   "sessions sorted by name",
+			output: "0\n0\n$2|web|0|0|0|shell|0|1|b25d,80x24,0,0,0|0|0|%1|1|4243|~/web|zsh\n$1|api|0|0|0|shell|0|1|b25d,80x24,0,0,0|0|0|%0|1|4242|~/api|zsh",
+			want: LoadStateResult{
+				Sessions: []Session{
+					{Name: "api", Windows: []Window{{Name: "shell", Path: "~/api", Layout: "b25d,80x24,0,0,0", Active: true, Panes: []Pane{{ID: "%0", Path: "~/api", Command: "zsh", PID: 4242, Active: true}}}}},
+					{Name: "web", Windows: []Window{{Name: "shell", Path: "~/web", Layout: "b25d,80x24,0,0,0", Active: true, Panes: []Pane{{ID: "%1", Path: "~/web", Command: "zsh", PID: 4243, Active: true}}}}},
+				},
+			},
+		},
+		{
+			name:   "zoomed window marks active pane",
+			output: "0\n0\n$1|dev|0|0|0|editor|0|1|ce49,80x24,0,0{40x24,0,0,0,39x24,41,0,1}|1|0|%0|0|4242|~/code|vim\n$1|dev|0|0|0|editor|0|1|ce49,80x24,0,0{40x24,0,0,0,39x24,41,0,1}|1|1|%1|1|4243|~/api|node",
+			want: LoadStateResult{
+				Sessions: []Session{{
+					Name: "dev",
+					Windows: []Window{{
+						Name:   "editor",
+						Index:  0,
+						Path:   "~/code",
+						Layout: "ce49,80x24,0,0{40x24,0,0,0,39x24,41,0,1}",
+						Active: true,
+						Panes: []Pane{
+							{ID: "%0", Path: "~/code", Command: "vim", PID: 4242},
+							{ID: "%1", Path: "~/api", Command: "node", PID: 4243, Zoom: true, Active: true},
+						},
+					}},
+				}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,18 +1,25 @@
 package tmux
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"strings"
 
 	"github.com/MSmaili/hetki/internal/backend"
 	"github.com/MSmaili/hetki/internal/plan"
+	"github.com/MSmaili/hetki/internal/proc"
 )
 
 type TmuxBackend struct {
 	client          Client
 	paneBaseIndex   int
 	windowBaseIndex int
+	// lastWindow is the highest window index of each session as of the last
+	// QueryState, so windows added to a running session get known indexes.
+	lastWindow map[string]int
 }
 
 func init() {
@@ -22,7 +29,7 @@ func init() {
 }
 
 func NewBackend() (*TmuxBackend, error) {
-	c, err := New()
+	c, err := NewControl()
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +50,15 @@ func (b *TmuxBackend) QueryState() (backend.StateResult, error) {
 		return backend.StateResult{}, err
 	}
 
+	b.lastWindow = make(map[string]int, len(result.Sessions))
+	for _, s := range result.Sessions {
+		for _, w := range s.Windows {
+			if last, ok := b.lastWindow[s.Name]; !ok || w.Index > last {
+				b.lastWindow[s.Name] = w.Index
+			}
+		}
+	}
+
 	sessions := make([]backend.Session, len(result.Sessions))
 	for i, s := range result.Sessions {
 		windows := make([]backend.Window, len(s.Windows))
@@ -51,20 +67,29 @@ func (b *TmuxBackend) QueryState() (backend.StateResult, error) {
 			for k, p := range w.Panes {
 				panes[k] = backend.Pane{
 					Index:   k,
+					ID:      p.ID,
 					Path:    p.Path,
 					Command: p.Command,
+					PID:     p.PID,
+					Zoom:    p.Zoom,
+					Active:  p.Active,
 				}
 			}
 			windows[j] = backend.Window{
 				Name:   w.Name,
+				Index:  w.Index,
 				Path:   w.Path,
 				Layout: w.Layout,
+				Active: w.Active,
 				Panes:  panes,
 			}
 		}
 		sessions[i] = backend.Session{
-			Name:    s.Name,
-			Windows: windows,
+			Name:     s.Name,
+			Attached: s.Attached,
+			Created:  s.Created,
+			Activity: s.Activity,
+			Windows:  windows,
 		}
 	}
 
@@ -80,12 +105,28 @@ func (b *TmuxBackend) QueryState() (backend.StateResult, error) {
 }
 
 func (b *TmuxBackend) Apply(actions []backend.Action) error {
-	tmuxActions := b.mapActions(actions)
-	return b.client.ExecuteBatch(tmuxActions)
+	tmuxActions, sources := b.mapActions(actions)
+	err := b.client.ExecuteBatch(tmuxActions)
+
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) && batchErr.Index < len(sources) {
+		failed := sources[batchErr.Index]
+		return &backend.ApplyError{
+			Failed:    actions[failed],
+			Completed: actions[:failed],
+			Skipped:   actions[failed+1:],
+			Err:       batchErr.Err,
+		}
+	}
+	return err
+}
+
+func (b *TmuxBackend) CapturePane(id string) (string, error) {
+	return RunQuery(b.client, CapturePaneQuery{Target: id})
 }
 
 func (b *TmuxBackend) DryRun(actions []backend.Action) []string {
-	tmuxActions := b.mapActions(actions)
+	tmuxActions, _ := b.mapActions(actions)
 	lines := make([]string, len(tmuxActions))
 	for i, a := range tmuxActions {
 		lines[i] = "tmux " + strings.Join(a.Args(), " ")
@@ -97,35 +138,40 @@ func (b *TmuxBackend) Attach(session string) error {
 	return b.switchTo(session)
 }
 
-func (b *TmuxBackend) Switch(target string) error {
-	session, rest, hasWindow := strings.Cut(target, ":")
-	if !hasWindow {
-		return b.switchTo(target)
-	}
-
-	state, err := RunQuery(b.client, LoadStateQuery{})
+// Switch resolves target against the running sessions and switches to it
+// by index, so window names with dots or colons need no escaping.
+func (b *TmuxBackend) Switch(target backend.Target) error {
+	state, err := b.QueryState()
 	if err != nil {
 		return err
 	}
-
-	window, paneStr, hasPane := strings.Cut(rest, ".")
-
-	winIndex, err := findWindowIndex(state.Sessions, session, window)
+	resolved, err := target.Resolve(state.Sessions)
 	if err != nil {
 		return err
 	}
 
-	resolved := fmt.Sprintf("%s:%d", session, winIndex)
-	if hasPane {
-		var pane int
-		fmt.Sscanf(paneStr, "%d", &pane)
-		resolved = fmt.Sprintf("%s.%d", resolved, pane+state.PaneBaseIndex)
+	tmuxTarget := "=" + resolved.Session
+	if resolved.Window != nil {
+		tmuxTarget = fmt.Sprintf("%s:%d", tmuxTarget, resolved.Window.Index)
 	}
+	if resolved.Pane >= 0 {
+		tmuxTarget = fmt.Sprintf("%s.%d", tmuxTarget, resolved.Pane+b.paneBaseIndex)
+	}
+	return b.switchTo(tmuxTarget)
+}
 
-	return b.switchTo(resolved)
+// Close ends the control-mode connection, if one is open.
+func (b *TmuxBackend) Close() error {
+	if closer, ok := b.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
 func (b *TmuxBackend) switchTo(target string) error {
+	// Attaching hands the terminal over to tmux, so there is no further use
+	// for a control-mode connection.
+	b.Close()
 	if isInsideTmux() {
 		return b.client.Execute(SwitchClient{Target: target})
 	}
@@ -136,54 +182,125 @@ func isInsideTmux() bool {
 	return os.Getenv("TMUX") != ""
 }
 
-func findWindowIndex(sessions []Session, sessionName, windowName string) (int, error) {
-	for _, s := range sessions {
-		if s.Name != sessionName {
-			continue
-		}
-		for _, w := range s.Windows {
-			if w.Name == windowName {
-				return w.Index, nil
-			}
+// mapActions converts plan actions to tmux actions. The second slice holds,
+// for each tmux action, the index of the plan action it came from.
+func (b *TmuxBackend) mapActions(actions []backend.Action) ([]Action, []int) {
+	result := make([]Action, 0, len(actions))
+	sources := make([]int, 0, len(actions))
+	windows := &createdWindows{
+		last:     maps.Clone(b.lastWindow),
+		current:  make(map[string]int),
+		panes:    make(map[string]int),
+		selected: make(map[string]bool),
+	}
+	if windows.last == nil {
+		windows.last = make(map[string]int)
+	}
+	for i, a := range actions {
+		if ta := b.mapAction(a, windows); ta != nil {
+			result = append(result, ta)
+			sources = append(sources, i)
 		}
-		return 0, fmt.Errorf("window %q not found in session %q", windowName, sessionName)
 	}
-	return 0, fmt.Errorf("session %q not found", sessionName)
+	return result, sources
 }
 
-func (b *TmuxBackend) mapActions(actions []backend.Action) []Action {
-	result := make([]Action, 0, len(actions))
-	windowIndex := make(map[string]int)
-	for _, a := range actions {
-		if ta := b.mapAction(a, windowIndex); ta != nil {
-			result = append(result, ta)
-		}
+// createdWindows tracks the windows a plan creates. Actions on panes always
+// follow the creation of their window, so they target the window most
+// recently created in their session by index rather than by name, which
+// may be ambiguous or look like tmux target syntax.
+type createdWindows struct {
+	last     map[string]int  // highest window index in use per session
+	current  map[string]int  // index of the window last created per session
+	panes    map[string]int  // panes in that window so far
+	selected map[string]bool // sessions whose active window the plan chose
+}
+
+func (w *createdWindows) create(session string, index int) {
+	w.last[session] = index
+	w.current[session] = index
+	w.panes[session] = 1
+}
+
+// window returns the target of the window last created in session, or false
+// when the plan created none.
+func (w *createdWindows) window(session string) (string, bool) {
+	index, ok := w.current[session]
+	return fmt.Sprintf("%s:%d", session, index), ok
+}
+
+// pane returns the target of a pane in the window last created in session,
+// or false when that pane was not created by the plan.
+func (w *createdWindows) pane(session string, pane, base int) (string, bool) {
+	target, ok := w.window(session)
+	if !ok || pane < 0 || pane >= w.panes[session] {
+		return "", false
 	}
-	return result
+	return fmt.Sprintf("%s.%d", target, pane+base), true
 }
 
-func (b *TmuxBackend) mapAction(a backend.Action, windowIndex map[string]int) Action {
-	base := b.windowBaseIndex
+func (b *TmuxBackend) mapAction(a backend.Action, windows *createdWindows) Action {
 	switch action := a.(type) {
 	case plan.CreateSessionAction:
-		windowIndex[action.Name] = base
+		windows.create(action.Name, b.windowBaseIndex)
 		return CreateSession{Name: action.Name, WindowName: action.WindowName, Path: action.Path}
 	case plan.CreateWindowAction:
-		windowIndex[action.Session]++
-		return CreateWindow{Session: action.Session, Name: action.Name, Path: action.Path}
+		index := b.windowBaseIndex
+		if last, ok := windows.last[action.Session]; ok {
+			index = max(last+1, index)
+		}
+		windows.create(action.Session, index)
+		return CreateWindow{
+			Session:  action.Session,
+			Index:    index,
+			Name:     action.Name,
+			Path:     action.Path,
+			Detached: windows.selected[action.Session],
+		}
 	case plan.SplitPaneAction:
-		return SplitPane{Target: fmt.Sprintf("%s:%d", action.Session, windowIndex[action.Session]), Path: action.Path}
+		target, ok := windows.window(action.Session)
+		if !ok {
+			return nil
+		}
+		windows.panes[action.Session]++
+		return SplitPane{Target: target, Path: action.Path}
 	case plan.SendKeysAction:
-		return SendKeys{Target: fmt.Sprintf("%s:%d.%d", action.Session, windowIndex[action.Session], action.Pane+b.paneBaseIndex), Keys: action.Command}
+		if target, ok := windows.pane(action.Session, action.Pane, b.paneBaseIndex); ok {
+			return SendKeys{Target: target, Keys: action.Command}
+		}
 	case plan.SelectLayoutAction:
-		return SelectLayout{Target: fmt.Sprintf("%s:%d", action.Session, windowIndex[action.Session]), Layout: action.Layout}
+		if target, ok := windows.window(action.Session); ok {
+			return SelectLayout{Target: target, Layout: action.Layout}
+		}
 	case plan.ZoomPaneAction:
-		return ZoomPane{Target: fmt.Sprintf("%s:%d.%d", action.Session, windowIndex[action.Session], action.Pane+b.paneBaseIndex)}
+		if target, ok := windows.pane(action.Session, action.Pane, b.paneBaseIndex); ok {
+			return ZoomPane{Target: target}
+		}
+	case plan.RestoreScrollbackAction:
+		// respawn-pane -k kills whatever runs in the pane, so it must only
+		// ever hit a pane this plan created.
+		if target, ok := windows.pane(action.Session, action.Pane, b.paneBaseIndex); ok {
+			return RespawnPane{Target: target, Path: action.Path, Command: replayCommand(action.File)}
+		}
 	case plan.KillSessionAction:
 		return KillSession{Name: action.Name}
 	case plan.KillWindowAction:
 		return KillWindow{Target: fmt.Sprintf("%s:%s", action.Session, action.Window)}
-	default:
-		return nil
+	case plan.SelectWindowAction:
+		if target, ok := windows.window(action.Session); ok {
+			windows.selected[action.Session] = true
+			return SelectWindow{Target: target}
+		}
+	case plan.SelectPaneAction:
+		if target, ok := windows.pane(action.Session, action.Pane, b.paneBaseIndex); ok {
+			return SelectPane{Target: target}
+		}
 	}
+	return nil
+}
+
+// replayCommand prints a saved scrollback file and then hands the pane over
+// to the user's shell, so the old history sits above the new prompt.
+func replayCommand(file string) string {
+	return fmt.Sprintf(`cat %s; exec "$SHELL"`, proc.ShellJoin([]string{file}))
 }
@@ -0,0 +1,136 @@
+package tmux
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/MSmaili/hetki/internal/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyAttributesBatchError(t *testing.T) {
+	actions := []backend.Action{
+		plan.CreateSessionAction{Name: "dev", WindowName: "editor", Path: "/code"},
+		unmappedAction{},
+		plan.CreateWindowAction{Session: "dev", Name: "server", Path: "/code"},
+		plan.SendKeysAction{Session: "dev", Window: "server", Command: "make run"},
+	}
+
+	mock := &MockClient{
+		ExecuteBatchFunc: func(actions []Action) error {
+			return &BatchError{Index: 1, Err: errors.New("can't find session: dev")}
+		},
+	}
+	b := &TmuxBackend{client: mock}
+
+	err := b.Apply(actions)
+
+	var applyErr *backend.ApplyError
+	require.ErrorAs(t, err, &applyErr)
+	assert.Equal(t, actions[2], applyErr.Failed)
+	assert.Equal(t, actions[:2], applyErr.Completed)
+	assert.Equal(t, actions[3:], applyErr.Skipped)
+	assert.Equal(t, "Create window: dev:server: can't find session: dev", err.Error())
+}
+
+func TestApplyPassesThroughUnattributedErrors(t *testing.T) {
+	mock := &MockClient{
+		ExecuteBatchFunc: func(actions []Action) error {
+			return errors.New("tmux source failed")
+		},
+	}
+	b := &TmuxBackend{client: mock}
+
+	err := b.Apply([]backend.Action{plan.KillSessionAction{Name: "old"}})
+
+	var applyErr *backend.ApplyError
+	assert.False(t, errors.As(err, &applyErr))
+	assert.EqualError(t, err, "tmux source failed")
+}
+
+func TestMapActionsWindowAddedToRunningSession(t *testing.T) {
+	mock := &MockClient{
+		RunFunc: func(args ...string) (string, error) {
+			return "1\n0\n$1|proj|0|0|0|code|1|1|b25d,80x24,0,0,0|0|0|%0|1|4242|~/proj|vim\n" +
+				"$1|proj|0|0|0|server|3|0|b25d,80x24,0,0,0|0|0|%1|1|4243|~/proj|node", nil
+		},
+	}
+	b := &TmuxBackend{client: mock}
+	_, err := b.QueryState()
+	require.NoError(t, err)
+
+	lines := b.DryRun([]backend.Action{
+		plan.CreateWindowAction{Session: "proj", Name: "logs", Path: "/var/log"},
+		plan.SplitPaneAction{Session: "proj", Window: "logs", Path: "/var/log"},
+		plan.RestoreScrollbackAction{Session: "proj", Window: "logs", Pane: 1, Path: "/var/log", File: "/tmp/1.log"},
+		plan.RestoreScrollbackAction{Session: "proj", Window: "logs", Pane: 2, Path: "/var/log", File: "/tmp/2.log"},
+		plan.SendKeysAction{Session: "proj", Window: "logs", Pane: 0, Command: "tail -f syslog"},
+	})
+
+	assert.Equal(t, []string{
+		"tmux new-window -t proj:4 -n logs -c /var/log",
+		"tmux split-window -t proj:4 -c /var/log",
+		`tmux respawn-pane -k -t proj:4.1 -c /var/log cat /tmp/1.log; exec "$SHELL"`,
+		"tmux send-keys -t proj:4.0 tail -f syslog Enter",
+	}, lines)
+}
+
+func TestMapActionsNewSessionUsesBaseIndex(t *testing.T) {
+	b := &TmuxBackend{windowBaseIndex: 1, paneBaseIndex: 1}
+
+	lines := b.DryRun([]backend.Action{
+		plan.CreateSessionAction{Name: "dev", WindowName: "editor"},
+		plan.CreateWindowAction{Session: "dev", Name: "server"},
+		plan.SplitPaneAction{Session: "dev", Window: "server"},
+		plan.ZoomPaneAction{Session: "dev", Window: "server", Pane: 1},
+	})
+
+	assert.Equal(t, []string{
+		"tmux new-session -d -s dev -n editor",
+		"tmux new-window -t dev:2 -n server",
+		"tmux split-window -t dev:2",
+		"tmux resize-pane -Z -t dev:2.2",
+	}, lines)
+}
+
+func TestMapActionsSkipsPanesOfWindowsNotCreated(t *testing.T) {
+	b := &TmuxBackend{lastWindow: map[string]int{"proj": 1}}
+
+	lines := b.DryRun([]backend.Action{
+		plan.RestoreScrollbackAction{Session: "proj", Window: "code", Pane: 0, File: "/tmp/0.log"},
+		plan.SendKeysAction{Session: "proj", Window: "code", Pane: 0, Command: "make"},
+	})
+
+	assert.Empty(t, lines)
+}
+
+func TestMapActionsSelectsByIndex(t *testing.T) {
+	b := &TmuxBackend{paneBaseIndex: 1, lastWindow: map[string]int{"p3": 0}}
+
+	lines := b.DryRun([]backend.Action{
+		plan.CreateWindowAction{Session: "p3", Name: "main"},
+		plan.CreateWindowAction{Session: "p3", Name: "main"},
+		plan.SplitPaneAction{Session: "p3", Window: "main"},
+		plan.SelectPaneAction{Session: "p3", Window: "main", Pane: 1},
+		plan.SelectWindowAction{Session: "p3", Window: "main"},
+		plan.CreateWindowAction{Session: "p3", Name: "v1.2"},
+		plan.CreateWindowAction{Session: "p3", Name: "2"},
+	})
+
+	assert.Equal(t, []string{
+		"tmux new-window -t p3:1 -n main",
+		"tmux new-window -t p3:2 -n main",
+		"tmux split-window -t p3:2",
+		"tmux select-pane -t p3:2.2",
+		"tmux select-window -t p3:2",
+		"tmux new-window -d -t p3:3 -n v1.2",
+		"tmux new-window -d -t p3:4 -n 2",
+	}, lines)
+}
+
+type unmappedAction struct{}
+
+func (unmappedAction) Comment() string { return "# Unmapped" }
+func (unmappedAction) Validate() error { return nil }
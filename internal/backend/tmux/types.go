@@ -5,10 +5,15 @@ type Window struct {
 	Index  int
 	Path   string
 	Layout string
+	Active bool
 	Panes  []Pane
 }
 
 type Pane struct {
+	ID      string
 	Path    string
 	Command string
+	PID     int
+	Zoom    bool
+	Active  bool
 }
@@ -1,26 +1,37 @@
 package backend
 
+import "time"
+
 type StateResult struct {
 	Sessions []Session
 	Active   ActiveContext
 }
 
 type Session struct {
-	Name    string
-	Windows []Window
+	Name     string
+	Attached bool
+	Created  time.Time
+	Activity time.Time
+	Windows  []Window
 }
 
 type Window struct {
 	Name   string
+	Index  int
 	Path   string
 	Layout string
+	Active bool
 	Panes  []Pane
 }
 
 type Pane struct {
 	Index   int
+	ID      string
 	Path    string
 	Command string
+	PID     int
+	Zoom    bool
+	Active  bool
 }
 
 type ActiveContext struct {
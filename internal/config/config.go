@@ -0,0 +1,184 @@
+// Package config locates hetki's configuration directory and reads the
+// global config file inside it.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MSmaili/hetki/internal/logger"
+	"github.com/MSmaili/hetki/internal/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dirName       = "hetki"
+	legacyDirName = "muxie"
+	fileName      = "config.yaml"
+)
+
+// Config holds user settings. Empty fields leave the built-in defaults in
+// place.
+type Config struct {
+	// WorkspacePaths are extra directories searched for named workspaces,
+	// after the workspaces directory in the config dir. Relative entries
+	// are resolved against the config dir.
+	WorkspacePaths []string `yaml:"workspace_paths,omitempty"`
+	Backend        string   `yaml:"backend,omitempty"`
+	Strategy       string   `yaml:"strategy,omitempty"`
+	Attach         *bool    `yaml:"attach,omitempty"`
+	SwitchStart    *bool    `yaml:"switch_start,omitempty"`
+	ListFormat     string   `yaml:"list_format,omitempty"`
+	Marker         string   `yaml:"marker,omitempty"`
+}
+
+// Dir returns the configuration directory: $HETKI_CONFIG_DIR if set, else
+// hetki under $XDG_CONFIG_HOME or ~/.config. Until MigrateLegacyDir has moved
+// it, a directory left behind by the old muxie name is used in its place.
+func Dir() (string, error) {
+	if dir := os.Getenv("HETKI_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	base, err := baseDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, dirName)
+	legacy := filepath.Join(base, legacyDirName)
+	if !exists(dir) && exists(legacy) {
+		return legacy, nil
+	}
+	return dir, nil
+}
+
+// MigrateLegacyDir moves a configuration directory left behind by the old
+// muxie name to where Dir looks for it. It does nothing when
+// $HETKI_CONFIG_DIR is set or the new directory already exists.
+func MigrateLegacyDir() error {
+	if os.Getenv("HETKI_CONFIG_DIR") != "" {
+		return nil
+	}
+	base, err := baseDir()
+	if err != nil {
+		return err
+	}
+	return migrateLegacyDir(filepath.Join(base, legacyDirName), filepath.Join(base, dirName))
+}
+
+func baseDir() (string, error) {
+	if base := os.Getenv("XDG_CONFIG_HOME"); base != "" {
+		return base, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+func migrateLegacyDir(legacy, dir string) error {
+	if exists(dir) || !exists(legacy) {
+		return nil
+	}
+	if err := os.Rename(legacy, dir); err != nil {
+		return fmt.Errorf("move %s to %s: %w", legacy, dir, err)
+	}
+	logger.Info("Moved configuration from %s to %s", legacy, dir)
+	return nil
+}
+
+// Path returns the location of the global config file.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// Load reads the global config file. A missing file yields an empty config.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Save writes cfg to path, creating the config dir if needed. An existing
+// file is edited in place: only settings whose value changed are rewritten,
+// so comments and keys hetki does not know about are kept.
+func (c *Config) Save(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	var old Config
+	if err := root.Decode(&old); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	var values yaml.Node
+	if err := values.Encode(c); err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	for _, s := range settings {
+		if s.get(c) == s.get(&old) {
+			continue
+		}
+		if value := yamlnode.MappingValue(&values, s.key); value != nil {
+			yamlnode.SetValue(root, s.key, value)
+		} else {
+			yamlnode.DeleteKey(root, s.key)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(yamlnode.DetectIndent(src))
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	enc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		want  string
+		setup func(t *testing.T)
+	}{
+		{
+			name: "explicit dir",
+			env:  map[string]string{"HETKI_CONFIG_DIR": "/etc/hetki", "XDG_CONFIG_HOME": xdg},
+			want: "/etc/hetki",
+		},
+		{
+			name: "xdg config home",
+			env:  map[string]string{"XDG_CONFIG_HOME": xdg},
+			want: filepath.Join(xdg, "hetki"),
+		},
+		{
+			name: "home fallback",
+			env:  map[string]string{"HOME": home},
+			want: filepath.Join(home, ".config", "hetki"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HETKI_CONFIG_DIR", "")
+			t.Setenv("XDG_CONFIG_HOME", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := Dir()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMigrateLegacyDir(t *testing.T) {
+	base := t.TempDir()
+	legacy := filepath.Join(base, "muxie")
+	dir := filepath.Join(base, "hetki")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(legacy, "workspaces"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacy, "workspaces", "dev.yaml"), []byte("sessions: []"), 0644))
+
+	require.NoError(t, migrateLegacyDir(legacy, dir))
+	assert.NoDirExists(t, legacy)
+	assert.FileExists(t, filepath.Join(dir, "workspaces", "dev.yaml"))
+
+	// An existing new directory is never overwritten.
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+	require.NoError(t, migrateLegacyDir(legacy, dir))
+	assert.DirExists(t, legacy)
+}
+
+func TestDirLeavesLegacyDirForMigration(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("HETKI_CONFIG_DIR", "")
+	t.Setenv("XDG_CONFIG_HOME", base)
+	legacy := filepath.Join(base, "muxie")
+	require.NoError(t, os.MkdirAll(legacy, 0755))
+
+	got, err := Dir()
+	require.NoError(t, err)
+	assert.Equal(t, legacy, got)
+	assert.DirExists(t, legacy, "looking up the dir must not move it")
+
+	require.NoError(t, MigrateLegacyDir())
+	got, err = Dir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, "hetki"), got)
+	assert.NoDirExists(t, legacy)
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadFile(filepath.Join(dir, "missing.yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, cfg.WorkspacePaths)
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("workspace_paths:\n  - ~/dotfiles/hetki\n  - shared\n"), 0644))
+
+	cfg, err = LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"~/dotfiles/hetki", "shared"}, cfg.WorkspacePaths)
+
+	require.NoError(t, os.WriteFile(path, []byte("workspace_paths: [\n"), 0644))
+	_, err = LoadFile(path)
+	assert.Error(t, err)
+}
+
+func TestConfigSetGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"strategy", "strategy", "force", "force", false},
+		{"invalid strategy", "strategy", "replace", "", true},
+		{"attach", "attach", "false", "false", false},
+		{"invalid attach", "attach", "sometimes", "", true},
+		{"switch start", "switch_start", "1", "true", false},
+		{"unset switch start", "switch_start", "", "", false},
+		{"invalid switch start", "switch_start", "maybe", "", true},
+		{"list format", "list_format", "tree", "tree", false},
+		{"list format template", "list_format", "template={{.Target}}", "template={{.Target}}", false},
+		{"workspace paths", "workspace_paths", "~/a, shared,", "~/a,shared", false},
+		{"unset", "marker", "", "", false},
+		{"unknown key", "colour", "red", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			err := cfg.Set(tt.key, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := cfg.Get(tt.key)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfigSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hetki", "config.yaml")
+
+	var cfg Config
+	require.NoError(t, cfg.Set("attach", "false"))
+	require.NoError(t, cfg.Set("backend", "tmux"))
+	require.NoError(t, cfg.Save(path))
+
+	loaded, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, &cfg, loaded)
+}
+
+func TestConfigSaveKeepsCommentsAndUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	src := `# hetki settings
+backend: tmux # detected anyway
+workspace_paths:
+  - ~/dotfiles/hetki # shared
+  - work
+theme: dark
+marker: "*"
+`
+	require.NoError(t, os.WriteFile(path, []byte(src), 0644))
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Set("strategy", "force"))
+	require.NoError(t, cfg.Set("marker", ""))
+	require.NoError(t, cfg.Set("backend", "zellij"))
+	require.NoError(t, cfg.Save(path))
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# hetki settings
+backend: zellij # detected anyway
+workspace_paths:
+  - ~/dotfiles/hetki # shared
+  - work
+theme: dark
+strategy: force
+`, string(got))
+}
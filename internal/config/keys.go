@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type setting struct {
+	key   string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{
+		key:   "attach",
+		usage: "Attach to the workspace after 'hetki start' (true, false)",
+		get:   func(c *Config) string { return formatBool(c.Attach) },
+		set: func(c *Config, v string) error {
+			return parseBool("attach", v, &c.Attach)
+		},
+	},
+	{
+		key:   "backend",
+		usage: "Multiplexer backend to use instead of detecting one (tmux)",
+		get:   func(c *Config) string { return c.Backend },
+		set: func(c *Config, v string) error {
+			c.Backend = v
+			return nil
+		},
+	},
+	{
+		key:   "list_format",
+		usage: "Default output format of 'hetki list' (flat, indent, tree, json, template=<text>)",
+		get:   func(c *Config) string { return c.ListFormat },
+		set: func(c *Config, v string) error {
+			if strings.HasPrefix(v, "template=") {
+				c.ListFormat = v
+				return nil
+			}
+			if err := oneOf("list_format", v, "flat", "indent", "tree", "json"); err != nil {
+				return err
+			}
+			c.ListFormat = v
+			return nil
+		},
+	},
+	{
+		key:   "marker",
+		usage: "Default prefix for the current session in 'hetki list'",
+		get:   func(c *Config) string { return c.Marker },
+		set: func(c *Config, v string) error {
+			c.Marker = v
+			return nil
+		},
+	},
+	{
+		key:   "strategy",
+		usage: "Default strategy of 'hetki start' (merge, force)",
+		get:   func(c *Config) string { return c.Strategy },
+		set: func(c *Config, v string) error {
+			if err := oneOf("strategy", v, "merge", "force"); err != nil {
+				return err
+			}
+			c.Strategy = v
+			return nil
+		},
+	},
+	{
+		key:   "switch_start",
+		usage: "Start the named workspace when 'hetki switch' targets a session that is not running (true, false)",
+		get:   func(c *Config) string { return formatBool(c.SwitchStart) },
+		set: func(c *Config, v string) error {
+			return parseBool("switch_start", v, &c.SwitchStart)
+		},
+	},
+	{
+		key:   "workspace_paths",
+		usage: "Comma-separated extra directories searched for named workspaces",
+		get:   func(c *Config) string { return strings.Join(c.WorkspacePaths, ",") },
+		set: func(c *Config, v string) error {
+			c.WorkspacePaths = nil
+			for _, p := range strings.Split(v, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					c.WorkspacePaths = append(c.WorkspacePaths, p)
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// Keys returns the names of all settings.
+func Keys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// Usage describes the setting key.
+func Usage(key string) string {
+	if s, err := lookup(key); err == nil {
+		return s.usage
+	}
+	return ""
+}
+
+// Get returns the value of key as text, or "" when it is not set.
+func (c *Config) Get(key string) (string, error) {
+	s, err := lookup(key)
+	if err != nil {
+		return "", err
+	}
+	return s.get(c), nil
+}
+
+// Set parses value and stores it under key. An empty value unsets it.
+func (c *Config) Set(key, value string) error {
+	s, err := lookup(key)
+	if err != nil {
+		return err
+	}
+	return s.set(c, value)
+}
+
+func lookup(key string) (setting, error) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, nil
+		}
+	}
+	return setting{}, fmt.Errorf("unknown config key %q\nValid keys: %s", key, strings.Join(Keys(), ", "))
+}
+
+func oneOf(key, value string, valid ...string) error {
+	if value == "" || slices.Contains(valid, value) {
+		return nil
+	}
+	return fmt.Errorf("invalid %s %q\nValid values: %s", key, value, strings.Join(valid, ", "))
+}
+
+func formatBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+// parseBool stores value in *dst, or nil when value is empty.
+func parseBool(key, value string, dst **bool) error {
+	if value == "" {
+		*dst = nil
+		return nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s must be true or false, got %q", key, value)
+	}
+	*dst = &b
+	return nil
+}
@@ -23,9 +23,12 @@ func manifestWindowToState(w manifest.Window, index int) *state.Window {
 	if name == "" {
 		name = fmt.Sprintf("window-%d", index)
 	}
-	window := &state.Window{Name: name, Path: w.Path, Layout: w.Layout}
+	window := &state.Window{Name: name, Path: w.Path, Layout: w.Layout, Focus: w.Focus}
 	for _, p := range w.Panes {
-		window.Panes = append(window.Panes, &state.Pane{Path: p.Path, Command: p.Command, Zoom: p.Zoom})
+		window.Panes = append(window.Panes, &state.Pane{Path: p.Path, Command: p.Command, Zoom: p.Zoom, Focus: p.Focus})
+	}
+	if len(window.Panes) == 0 && w.Command != "" {
+		window.Panes = []*state.Pane{{Path: w.Path, Command: w.Command}}
 	}
 	return window
 }
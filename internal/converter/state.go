@@ -60,9 +60,9 @@ func convertWindowDiff(wd state.ItemDiff[state.Window]) plan.ItemDiff[plan.Windo
 }
 
 func StateWindowToPlan(w *state.Window) plan.Window {
-	pw := plan.Window{Name: w.Name, Path: w.Path, Layout: w.Layout}
+	pw := plan.Window{Name: w.Name, Path: w.Path, Layout: w.Layout, Focus: w.Focus}
 	for _, p := range w.Panes {
-		pw.Panes = append(pw.Panes, plan.Pane{Path: p.Path, Command: p.Command, Zoom: p.Zoom})
+		pw.Panes = append(pw.Panes, plan.Pane{Path: p.Path, Command: p.Command, Zoom: p.Zoom, Focus: p.Focus, Scrollback: p.Scrollback})
 	}
 	return pw
 }
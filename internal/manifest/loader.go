@@ -7,15 +7,12 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/MSmaili/hetki/internal/config"
 	"gopkg.in/yaml.v3"
 )
 
 func GetConfigDir() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".config", "muxie"), nil
+	return config.Dir()
 }
 
 type Loader interface {
@@ -37,27 +34,41 @@ func (l *FileLoader) Load() (*Workspace, error) {
 		return nil, fmt.Errorf("read config: %w", err)
 	}
 
+	raw, err := parse(data, filepath.Ext(extendedPath))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validate(raw); err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(extendedPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolving config path: %w", err)
+	}
+	return normalize(raw, filepath.Dir(absPath))
+}
+
+// parse decodes a manifest in the format given by ext without validating or
+// normalizing it.
+func parse(data []byte, ext string) (*Workspace, error) {
 	var raw Workspace
-	ext := filepath.Ext(extendedPath)
 
 	switch ext {
 	case ".yaml", ".yml":
-		if err = yaml.Unmarshal(data, &raw); err != nil {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
 			return nil, fmt.Errorf("parse yaml config: %w", err)
 		}
 	case ".json":
-		if err = json.Unmarshal(data, &raw); err != nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
 			return nil, fmt.Errorf("parse json config: %w", err)
 		}
 	default:
 		return nil, fmt.Errorf("unsupported config format: %s (use .yaml, .yml, or .json)", ext)
 	}
 
-	if err = validate(&raw); err != nil {
-		return nil, err
-	}
-
-	return normalize(&raw)
+	return &raw, nil
 }
 
 func validate(cfg *Workspace) error {
@@ -82,21 +93,36 @@ func validate(cfg *Workspace) error {
 	return nil
 }
 
-func normalize(cfg *Workspace) (*Workspace, error) {
-	out := &Workspace{Sessions: make([]Session, len(cfg.Sessions))}
+// normalize fills in defaults and makes every path absolute. Relative roots
+// are resolved against baseDir, the directory of the manifest, and relative
+// window and pane paths against the session root, or baseDir without one.
+func normalize(cfg *Workspace, baseDir string) (*Workspace, error) {
+	out := &Workspace{Sessions: make([]Session, len(cfg.Sessions)), Attach: cfg.Attach}
 
 	for i, sess := range cfg.Sessions {
-		sess.Root = expandPath(sess.Root)
+		sess.Root = resolvePath(baseDir, sess.Root)
+		base := sess.Root
+		if base == "" {
+			base = baseDir
+		}
 		normalized := make([]Window, len(sess.Windows))
 
 		for j, w := range sess.Windows {
 			if w.Path == "" {
 				w.Path = sess.Root
 			}
-			w.Path = expandPath(w.Path)
+			w.Path = resolvePath(base, w.Path)
 			if w.Name == "" {
 				w.Name = inferNameFromPath(w.Path)
 			}
+			if len(w.Panes) > 0 {
+				panes := make([]Pane, len(w.Panes))
+				for k, p := range w.Panes {
+					p.Path = resolvePath(base, p.Path)
+					panes[k] = p
+				}
+				w.Panes = panes
+			}
 			normalized[j] = w
 		}
 		sess.Windows = normalized
@@ -127,6 +153,24 @@ func expandPath(p string) string {
 	return p
 }
 
+// ScanWorkspaceDirs maps workspace names to files across dirs. When a name
+// appears in several directories the earlier one wins, matching Resolver.
+func ScanWorkspaceDirs(dirs []string) (map[string]string, error) {
+	paths := make(map[string]string)
+	for _, dir := range dirs {
+		found, err := ScanWorkspaces(dir)
+		if err != nil {
+			return nil, err
+		}
+		for name, path := range found {
+			if _, ok := paths[name]; !ok {
+				paths[name] = path
+			}
+		}
+	}
+	return paths, nil
+}
+
 func ScanWorkspaces(dir string) (map[string]string, error) {
 	expandedDir := expandPath(dir)
 	entries, err := os.ReadDir(expandedDir)
@@ -162,5 +206,5 @@ func loadFromMemory(data []byte) (*Workspace, error) {
 		return nil, err
 	}
 
-	return normalize(&raw)
+	return normalize(&raw, "")
 }
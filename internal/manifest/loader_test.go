@@ -148,6 +148,64 @@ func TestLoadYAMLWithSessionRoot(t *testing.T) {
 	assert.Equal(t, "/other/path", workspace.Sessions[0].Windows[1].Path)
 }
 
+func TestLoadYAMLRelativeToSessionRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "test.yaml")
+
+	yamlContent := `sessions:
+  - name: myapp
+    root: /home/user/code
+    windows:
+      - name: api
+        path: services/api
+        panes:
+          - path: .
+          - path: services/api/tests
+          - path: /var/log
+`
+
+	err := os.WriteFile(configPath, []byte(yamlContent), 0644)
+	require.NoError(t, err)
+
+	workspace, err := NewFileLoader(configPath).Load()
+	require.NoError(t, err)
+
+	w := workspace.Sessions[0].Windows[0]
+	assert.Equal(t, "/home/user/code/services/api", w.Path)
+	assert.Equal(t, "/home/user/code", w.Panes[0].Path)
+	assert.Equal(t, "/home/user/code/services/api/tests", w.Panes[1].Path)
+	assert.Equal(t, "/var/log", w.Panes[2].Path)
+}
+
+func TestLoadYAMLRelativeToManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".hetki.yaml")
+
+	yamlContent := `sessions:
+  - name: project
+    root: .
+    windows:
+      - name: editor
+      - name: api
+        path: services/api
+  - name: docs
+    windows:
+      - name: site
+        path: docs
+`
+
+	err := os.WriteFile(configPath, []byte(yamlContent), 0644)
+	require.NoError(t, err)
+
+	workspace, err := NewFileLoader(configPath).Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, tmpDir, workspace.Sessions[0].Root)
+	assert.Equal(t, tmpDir, workspace.Sessions[0].Windows[0].Path)
+	assert.Equal(t, filepath.Join(tmpDir, "services/api"), workspace.Sessions[0].Windows[1].Path)
+	assert.Equal(t, filepath.Join(tmpDir, "docs"), workspace.Sessions[1].Windows[0].Path)
+}
+
 func TestScanWorkspaces(t *testing.T) {
 	t.Run("scans directory with multiple files", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -0,0 +1,278 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/MSmaili/hetki/internal/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+// MergeYAML updates the YAML workspace document src with the sessions in
+// live, which describe what is running right now. Only fields derived from
+// live state are touched: window names, paths, layouts, commands, panes and
+// zoom. Comments, key order and manifest-only fields such as root, index,
+// split and size are kept.
+//
+// Every value is compared three ways: the raw value in src, the value src
+// resolves to once loaded, and the live value. A field is only rewritten
+// when the resolved value differs from the live one, so an inherited root
+// or a path written with ~ or $VARS survives a save. Live state cannot tell
+// an exited command from no command, so empty live commands never clear a
+// hand-written one, and a hand-written layout is kept while the pane count
+// still matches. Paths that do change are written relative to the session
+// root when they fall inside it.
+//
+// dir is the directory of the manifest, used to resolve relative roots. With
+// removeMissing, sessions in src that are not in live are dropped. Otherwise
+// they are left as they are.
+func MergeYAML(src []byte, dir string, live *Workspace, removeMissing bool) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, fmt.Errorf("parse yaml: %w", err)
+	}
+
+	var raw Workspace
+	if err := doc.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("parse yaml: %w", err)
+	}
+	resolved, err := normalize(&raw, dir)
+	if err != nil {
+		return nil, err
+	}
+	liveResolved, err := normalize(live, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("workspace must be a mapping")
+	}
+
+	sessions := yamlnode.MappingValue(root, "sessions")
+	if sessions == nil || sessions.Kind != yaml.SequenceNode {
+		sessions = &yaml.Node{Kind: yaml.SequenceNode}
+		yamlnode.SetValue(root, "sessions", sessions)
+	}
+
+	if err := mergeSessions(sessions, raw.Sessions, resolved.Sessions, live.Sessions, liveResolved.Sessions, removeMissing); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(yamlnode.DetectIndent(src))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("marshal yaml: %w", err)
+	}
+	enc.Close()
+	return buf.Bytes(), nil
+}
+
+func mergeSessions(seq *yaml.Node, raw, resolved, live, liveResolved []Session, removeMissing bool) error {
+	if len(seq.Content) != len(raw) {
+		return fmt.Errorf("unexpected sessions layout")
+	}
+
+	byName := make(map[string]int, len(raw))
+	for i, s := range raw {
+		byName[s.Name] = i
+	}
+
+	keep := make([]bool, len(seq.Content))
+	for k, sess := range liveResolved {
+		i, ok := byName[sess.Name]
+		if !ok {
+			node, err := encodeNode(live[k])
+			if err != nil {
+				return err
+			}
+			seq.Content = append(seq.Content, node)
+			continue
+		}
+		keep[i] = true
+		if err := mergeSession(seq.Content[i], raw[i], resolved[i], sess); err != nil {
+			return err
+		}
+	}
+
+	if removeMissing {
+		content := seq.Content[:0]
+		for i, node := range seq.Content {
+			if i >= len(keep) || keep[i] {
+				content = append(content, node)
+			}
+		}
+		seq.Content = content
+	}
+	return nil
+}
+
+func mergeSession(node *yaml.Node, raw, resolved, live Session) error {
+	windows := yamlnode.MappingValue(node, "windows")
+	if windows == nil || windows.Kind != yaml.SequenceNode || len(windows.Content) != len(raw.Windows) {
+		return fmt.Errorf("unexpected windows layout in session %q", raw.Name)
+	}
+
+	used := make([]bool, len(raw.Windows))
+	content := make([]*yaml.Node, 0, len(live.Windows))
+
+	for _, w := range live.Windows {
+		j := -1
+		for k, rw := range resolved.Windows {
+			if !used[k] && rw.Name == w.Name {
+				j = k
+				break
+			}
+		}
+
+		if j == -1 {
+			newNode, err := encodeNode(relativeWindow(resolved.Root, w))
+			if err != nil {
+				return err
+			}
+			content = append(content, newNode)
+			continue
+		}
+
+		used[j] = true
+		if err := mergeWindow(windows.Content[j], resolved.Root, raw.Windows[j], resolved.Windows[j], w); err != nil {
+			return err
+		}
+		content = append(content, windows.Content[j])
+	}
+
+	windows.Content = content
+	return nil
+}
+
+// mergeWindow updates node with the live window. Paths in live are
+// absolute; new ones are written relative to root where possible.
+func mergeWindow(node *yaml.Node, root string, raw, resolved, live Window) error {
+	if resolved.Name != live.Name {
+		setString(node, "name", live.Name)
+	}
+	if resolved.Path != live.Path {
+		if path := pathIn(root, live.Path); path == "." {
+			yamlnode.DeleteKey(node, "path")
+		} else {
+			setString(node, "path", path)
+		}
+	}
+
+	livePanes := max(1, len(live.Panes))
+	rawPanes := max(1, len(raw.Panes))
+
+	if rawPanes != livePanes || raw.Layout == "" {
+		if live.Layout != "" {
+			setString(node, "layout", live.Layout)
+		} else if rawPanes != livePanes {
+			yamlnode.DeleteKey(node, "layout")
+		}
+	}
+
+	if len(live.Panes) == 0 {
+		if len(raw.Panes) > 1 {
+			yamlnode.DeleteKey(node, "panes")
+		}
+		if len(raw.Panes) == 1 {
+			return mergePanes(yamlnode.MappingValue(node, "panes"), root, resolved.Panes, []Pane{{Path: live.Path, Command: live.Command}})
+		}
+		if live.Command != "" && live.Command != raw.Command {
+			setString(node, "command", live.Command)
+		}
+		return nil
+	}
+
+	panes := yamlnode.MappingValue(node, "panes")
+	if panes == nil || panes.Kind != yaml.SequenceNode {
+		if raw.Command != "" && live.Panes[0].Command == "" {
+			live.Panes[0].Command = raw.Command
+		}
+		yamlnode.DeleteKey(node, "command")
+		newNode, err := encodeNode(relativeWindow(root, live).Panes)
+		if err != nil {
+			return err
+		}
+		yamlnode.SetValue(node, "panes", newNode)
+		return nil
+	}
+	return mergePanes(panes, root, resolved.Panes, live.Panes)
+}
+
+func mergePanes(seq *yaml.Node, root string, resolved, live []Pane) error {
+	if seq == nil || len(seq.Content) != len(resolved) {
+		return fmt.Errorf("unexpected panes layout")
+	}
+
+	content := make([]*yaml.Node, 0, len(live))
+	for i, p := range live {
+		if i >= len(resolved) {
+			newNode, err := encodeNode(Pane{Path: pathIn(root, p.Path), Command: p.Command, Zoom: p.Zoom})
+			if err != nil {
+				return err
+			}
+			content = append(content, newNode)
+			continue
+		}
+
+		node := seq.Content[i]
+		if resolved[i].Path != p.Path {
+			setString(node, "path", pathIn(root, p.Path))
+		}
+		if p.Command != "" && p.Command != resolved[i].Command {
+			setString(node, "command", p.Command)
+		}
+		if p.Zoom {
+			yamlnode.SetValue(node, "zoom", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		} else {
+			yamlnode.DeleteKey(node, "zoom")
+		}
+		content = append(content, node)
+	}
+
+	seq.Content = content
+	return nil
+}
+
+// relativeWindow returns w with its paths written relative to root.
+func relativeWindow(root string, w Window) Window {
+	w.Path = pathIn(root, w.Path)
+	if w.Path == "." {
+		w.Path = ""
+	}
+	panes := make([]Pane, len(w.Panes))
+	for i, p := range w.Panes {
+		p.Path = pathIn(root, p.Path)
+		panes[i] = p
+	}
+	w.Panes = panes
+	return w
+}
+
+// pathIn expresses an absolute path the way a manifest with the given
+// session root would: relative to root when inside it, else with ~.
+func pathIn(root, path string) string {
+	if root != "" {
+		if rel, ok := RelativePath(root, path); ok {
+			return rel
+		}
+	}
+	return ContractHome(path)
+}
+
+func encodeNode(v any) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, fmt.Errorf("marshal yaml: %w", err)
+	}
+	return &node, nil
+}
+
+func setString(m *yaml.Node, key, value string) {
+	yamlnode.SetValue(m, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+}
@@ -0,0 +1,123 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mergeSource = `# my workspaces
+sessions:
+  - name: dev
+    root: /code
+    windows:
+      # main editor
+      - name: editor
+        command: nvim # started by hand
+      - name: server
+        path: /code/api
+        layout: main-vertical
+        panes:
+          - path: /code/api
+            command: npm run dev
+          - path: /code/api
+            size: 30
+      - name: logs
+        path: /var/log
+  - name: notes
+    windows:
+      - path: /notes
+`
+
+func TestMergeYAML(t *testing.T) {
+	tests := []struct {
+		name          string
+		live          *Workspace
+		removeMissing bool
+		want          string
+	}{
+		{
+			name: "unchanged state keeps the document",
+			live: &Workspace{Sessions: []Session{{Name: "dev", Windows: []Window{
+				{Name: "editor", Path: "/code"},
+				{Name: "server", Path: "/code/api", Layout: "b25d,80x24,0,0,0", Panes: []Pane{
+					{Path: "/code/api", Command: "npm run dev"},
+					{Path: "/code/api"},
+				}},
+				{Name: "logs", Path: "/var/log"},
+			}}}},
+			want: mergeSource,
+		},
+		{
+			name: "updates live fields and drops closed windows",
+			live: &Workspace{Sessions: []Session{{Name: "dev", Windows: []Window{
+				{Name: "editor", Path: "/code/web"},
+				{Name: "server", Path: "/code/api", Layout: "c3d1,80x24,0,0", Panes: []Pane{
+					{Path: "/code/api", Command: "npm run dev"},
+					{Path: "/code/api", Zoom: true},
+					{Path: "/tmp", Command: "htop"},
+				}},
+				{Name: "shell", Path: "/home"},
+			}}}},
+			want: `# my workspaces
+sessions:
+  - name: dev
+    root: /code
+    windows:
+      # main editor
+      - name: editor
+        command: nvim # started by hand
+        path: web
+      - name: server
+        path: /code/api
+        layout: c3d1,80x24,0,0
+        panes:
+          - path: /code/api
+            command: npm run dev
+          - path: /code/api
+            size: 30
+            zoom: true
+          - path: /tmp
+            command: htop
+      - name: shell
+        path: /home
+  - name: notes
+    windows:
+      - path: /notes
+`,
+		},
+		{
+			name: "adds new sessions and removes missing ones",
+			live: &Workspace{Sessions: []Session{
+				{Name: "notes", Windows: []Window{{Name: "notes", Path: "/notes", Command: "vim todo.md"}}},
+				{Name: "ops", Windows: []Window{{Name: "k9s", Path: "/ops"}}},
+			}},
+			removeMissing: true,
+			want: `# my workspaces
+sessions:
+  - name: notes
+    windows:
+      - path: /notes
+        command: vim todo.md
+  - name: ops
+    windows:
+      - name: k9s
+        path: /ops
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeYAML([]byte(mergeSource), "/workspaces", tt.live, tt.removeMissing)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestMergeYAMLInvalid(t *testing.T) {
+	_, err := MergeYAML([]byte("sessions: [\n"), "/workspaces", &Workspace{}, false)
+	assert.Error(t, err)
+}
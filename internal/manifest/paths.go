@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContractHome replaces a leading home directory in path with ~.
+func ContractHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if strings.HasPrefix(path, home+"/") {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	if path == home {
+		return "~"
+	}
+	return path
+}
+
+// RelativePath returns path relative to root when path lies inside root,
+// and ok is false otherwise. Both must be absolute.
+func RelativePath(root, path string) (rel string, ok bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+	return rel, true
+}
+
+// resolvePath expands p and joins it onto root when it is relative.
+func resolvePath(root, p string) string {
+	p = expandPath(p)
+	if p == "" || root == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(root, p)
+}
@@ -5,17 +5,27 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/MSmaili/hetki/internal/config"
 )
 
 const DefaultExt = ".yaml"
 
 type Resolver struct {
 	configDir func() (string, error)
+	// workspaceDirs lists where named workspaces are looked up. Without it
+	// only the workspaces directory in configDir is searched.
+	workspaceDirs func() ([]string, error)
+	// stopAt reports whether the search for a local workspace should not
+	// continue above dir.
+	stopAt func(dir string) bool
 }
 
 func NewResolver() *Resolver {
 	return &Resolver{
-		configDir: GetConfigDir,
+		configDir:     GetConfigDir,
+		workspaceDirs: WorkspaceDirs,
+		stopAt:        isSearchBoundary,
 	}
 }
 
@@ -74,34 +84,89 @@ func (r *Resolver) findNamedWorkspace(name string) (string, error) {
 		return filepath.Abs(name)
 	}
 
-	configDir, err := r.configDir()
+	dirs, err := r.searchDirs()
 	if err != nil {
 		return "", err
 	}
 
-	workspacesDir := filepath.Join(configDir, "workspaces")
-	for _, ext := range []string{".yaml", ".yml", ".json"} {
-		path := filepath.Join(workspacesDir, name+ext)
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
+	for _, dir := range dirs {
+		for _, ext := range []string{".yaml", ".yml", ".json"} {
+			path := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
 		}
 	}
 
-	return "", fmt.Errorf("named workspace not found: %s\nHint: List available workspaces with 'muxie list' or create one with 'muxie save -n %s'", name, name)
+	return "", fmt.Errorf("named workspace not found: %s\nHint: List available workspaces with 'hetki list' or create one with 'hetki save -n %s'", name, name)
 }
 
+func (r *Resolver) searchDirs() ([]string, error) {
+	if r.workspaceDirs != nil {
+		return r.workspaceDirs()
+	}
+	configDir, err := r.configDir()
+	if err != nil {
+		return nil, err
+	}
+	return []string{filepath.Join(configDir, "workspaces")}, nil
+}
+
+// WorkspaceDirs returns the directories named workspaces are looked up in,
+// in order of precedence: the workspaces directory in the config dir, where
+// new named workspaces are saved, followed by workspace_paths from the
+// global config file. A config file that cannot be read adds no
+// directories; the CLI warns about it once on startup.
+func WorkspaceDirs() ([]string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("getting config dir: %w", err)
+	}
+
+	dirs := []string{filepath.Join(configDir, "workspaces")}
+	cfg, err := config.Load()
+	if err != nil {
+		return dirs, nil
+	}
+	for _, p := range cfg.WorkspacePaths {
+		dirs = append(dirs, resolvePath(configDir, p))
+	}
+	return dirs, nil
+}
+
+// findLocalWorkspace looks for .hetki.{yaml,yml,json} in the current
+// directory and its parents, up to the first boundary directory.
 func (r *Resolver) findLocalWorkspace() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", err
 	}
 
-	for _, ext := range []string{".yaml", ".yml", ".json"} {
-		path := filepath.Join(cwd, ".hetki"+ext)
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
+	dir := cwd
+	for {
+		for _, ext := range []string{".yaml", ".yml", ".json"} {
+			path := filepath.Join(dir, ".hetki"+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
 		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir || (r.stopAt != nil && r.stopAt(dir)) {
+			break
+		}
+		dir = parent
 	}
 
-	return "", fmt.Errorf("no local workspace found (.hetki.{yaml,yml,json})\nHint: Create one with 'hetki save .' or specify a workspace name")
+	return "", fmt.Errorf("no local workspace found (.hetki.{yaml,yml,json}) in %s or its parents up to %s\nHint: Create one with 'hetki save .' or specify a workspace name", cwd, dir)
+}
+
+// isSearchBoundary stops the upward search at the home directory and at the
+// top of a git repository.
+func isSearchBoundary(dir string) bool {
+	if home, err := os.UserHomeDir(); err == nil && dir == home {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
 }
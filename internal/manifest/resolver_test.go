@@ -71,6 +71,52 @@ func TestResolverLocalWorkspace(t *testing.T) {
 	assert.Equal(t, expectedPath, actualPath)
 }
 
+func TestResolverLocalWorkspaceInParent(t *testing.T) {
+	tmpDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+
+	project := filepath.Join(tmpDir, "project")
+	nested := filepath.Join(project, "src", "pkg")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.Chdir(nested))
+
+	localPath := filepath.Join(project, ".hetki.yaml")
+	require.NoError(t, os.WriteFile(localPath, []byte("sessions: {}"), 0644))
+
+	tests := []struct {
+		name    string
+		stopAt  func(dir string) bool
+		want    string
+		wantErr bool
+	}{
+		{"found in ancestor", func(string) bool { return false }, localPath, false},
+		{"stops at boundary", func(dir string) bool { return dir == filepath.Join(project, "src") }, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &Resolver{configDir: func() (string, error) { return tmpDir, nil }, stopAt: tt.stopAt}
+			resolved, err := resolver.Resolve("")
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "no local workspace found")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resolved)
+		})
+	}
+}
+
+func TestIsSearchBoundary(t *testing.T) {
+	repo := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+
+	assert.True(t, isSearchBoundary(repo))
+	assert.False(t, isSearchBoundary(t.TempDir()))
+}
+
 func TestResolverNotFound(t *testing.T) {
 	resolver := NewResolver()
 	_, err := resolver.Resolve("nonexistent-workspace")
@@ -109,6 +155,48 @@ func TestResolverPrefersNamedOverLocal(t *testing.T) {
 	assert.Equal(t, namedPath, resolved)
 }
 
+func TestResolverSearchesWorkspaceDirs(t *testing.T) {
+	primary := t.TempDir()
+	shared := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(primary, "dev.yaml"), []byte("sessions: {}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "dev.yaml"), []byte("sessions: {}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "team.yml"), []byte("sessions: {}"), 0644))
+
+	dirs := []string{primary, shared}
+	resolver := &Resolver{workspaceDirs: func() ([]string, error) { return dirs, nil }}
+
+	resolved, err := resolver.Resolve("team")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(shared, "team.yml"), resolved)
+
+	resolved, err = resolver.Resolve("dev")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(primary, "dev.yaml"), resolved, "earlier directories take precedence")
+
+	names, err := ScanWorkspaceDirs(dirs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"dev":  filepath.Join(primary, "dev.yaml"),
+		"team": filepath.Join(shared, "team.yml"),
+	}, names)
+}
+
+func TestWorkspaceDirsIgnoresBrokenConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HETKI_CONFIG_DIR", dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("workspace_paths: [shared\n"), 0644))
+	dirs, err := WorkspaceDirs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "workspaces")}, dirs)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("workspace_paths: [shared]\n"), 0644))
+	dirs, err = WorkspaceDirs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "workspaces"), filepath.Join(dir, "shared")}, dirs)
+}
+
 func TestExpandPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,93 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/MSmaili/hetki/internal/backend"
+)
+
+// AttachTarget returns where to attach after starting the workspace: the
+// attach setting, else the first window with focus, else the first pane
+// with focus, else the first session. Targets are written
+// session[:window[.pane]] with the window by name and the pane by its
+// position in the window, counting from 0.
+func (ws *Workspace) AttachTarget() string {
+	if ws.Attach != "" {
+		return ws.Attach
+	}
+	for _, sess := range ws.Sessions {
+		for _, w := range sess.Windows {
+			if w.Focus {
+				return sess.Name + ":" + w.Name
+			}
+		}
+	}
+	for _, sess := range ws.Sessions {
+		for _, w := range sess.Windows {
+			for i, p := range w.Panes {
+				if p.Focus {
+					return fmt.Sprintf("%s:%s.%d", sess.Name, w.Name, i)
+				}
+			}
+		}
+	}
+	if len(ws.Sessions) > 0 {
+		return ws.Sessions[0].Name
+	}
+	return ""
+}
+
+// ValidateTarget checks that target, given on the command line, names a
+// session, window and pane of the workspace. It is read the way attaching
+// reads it, by backend.ParseTarget, so both session:window:pane and
+// session:window.pane work, and matched the same way, by exact name, unique
+// prefix or fuzzy.
+func ValidateTarget(ws *Workspace, target string) error {
+	return validateTarget(ws, target, false)
+}
+
+// ParseAttachTarget reads a target written in the manifest, like
+// AttachTarget returns it. Such targets name sessions and windows exactly:
+// they are matched against every running session when attaching, where a
+// prefix or fuzzy match could mean another session.
+func ParseAttachTarget(target string) (backend.Target, error) {
+	t, err := backend.ParseTarget(target, ":")
+	if err != nil {
+		return backend.Target{}, err
+	}
+	t.Exact = true
+	return t, nil
+}
+
+func validateTarget(ws *Workspace, target string, exact bool) error {
+	t, err := backend.ParseTarget(target, ":")
+	if err != nil {
+		return err
+	}
+	t.Exact = exact
+	if _, err := t.Resolve(targetSessions(ws)); err != nil {
+		return fmt.Errorf("target %q: %w", target, err)
+	}
+	return nil
+}
+
+// targetSessions describes ws as running sessions. Windows without an
+// index get -1, since their index is only known once they run.
+func targetSessions(ws *Workspace) []backend.Session {
+	sessions := make([]backend.Session, len(ws.Sessions))
+	for i, sess := range ws.Sessions {
+		sessions[i].Name = sess.Name
+		for _, w := range sess.Windows {
+			index := -1
+			if w.Index != nil {
+				index = *w.Index
+			}
+			sessions[i].Windows = append(sessions[i].Windows, backend.Window{
+				Name:  w.Name,
+				Index: index,
+				Panes: make([]backend.Pane, max(1, len(w.Panes))),
+			})
+		}
+	}
+	return sessions
+}
@@ -0,0 +1,109 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/MSmaili/hetki/internal/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func targetWorkspace() *Workspace {
+	return &Workspace{Sessions: []Session{
+		{Name: "api", Windows: []Window{{Name: "editor"}, {Name: "server", Panes: []Pane{{}, {}}}}},
+		{Name: "web", Windows: []Window{{Name: "v1.2"}, {Name: "logs"}}},
+	}}
+}
+
+func TestAttachTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(ws *Workspace)
+		expected string
+	}{
+		{
+			name:     "first session by default",
+			modify:   func(ws *Workspace) {},
+			expected: "api",
+		},
+		{
+			name:     "focused window",
+			modify:   func(ws *Workspace) { ws.Sessions[1].Windows[1].Focus = true },
+			expected: "web:logs",
+		},
+		{
+			name:     "focused pane",
+			modify:   func(ws *Workspace) { ws.Sessions[0].Windows[1].Panes[1].Focus = true },
+			expected: "api:server.1",
+		},
+		{
+			name: "focused window wins over focused pane",
+			modify: func(ws *Workspace) {
+				ws.Sessions[0].Windows[1].Panes[1].Focus = true
+				ws.Sessions[1].Windows[0].Focus = true
+			},
+			expected: "web:v1.2",
+		},
+		{
+			name: "attach wins over focus",
+			modify: func(ws *Workspace) {
+				ws.Attach = "web"
+				ws.Sessions[0].Windows[1].Focus = true
+			},
+			expected: "web",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := targetWorkspace()
+			tt.modify(ws)
+			assert.Equal(t, tt.expected, ws.AttachTarget())
+		})
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr string
+	}{
+		{target: "api"},
+		{target: "api:editor"},
+		{target: "api:editor.0"},
+		{target: "api:server.1"},
+		{target: "web:v1.2"},
+		{target: "api:server:1"},
+		{target: "web:v1.2:0"},
+		{target: "ap:serv"},
+		{target: "db", wantErr: `session "db" not found`},
+		{target: "api:logs", wantErr: `window "logs" not found`},
+		{target: "api:server.2", wantErr: "has no pane 2"},
+		{target: "api:server:2", wantErr: "has no pane 2"},
+		{target: "api:editor.1", wantErr: "has no pane 1"},
+		{target: "api:server:x", wantErr: "pane must be a number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			err := ValidateTarget(targetWorkspace(), tt.target)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseAttachTarget(t *testing.T) {
+	target, err := ParseAttachTarget("api:server.1")
+	require.NoError(t, err)
+	assert.True(t, target.Exact)
+
+	_, err = target.Resolve([]backend.Session{{Name: "api-docs"}})
+	assert.ErrorContains(t, err, `session "api" not found`)
+
+	_, err = ParseAttachTarget(":server")
+	assert.ErrorContains(t, err, "missing session")
+}
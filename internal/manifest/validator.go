@@ -41,6 +41,12 @@ func Validate(ws *Workspace) []ValidationError {
 		errs = validateSession(sess, seenSessions, errs)
 	}
 
+	if ws.Attach != "" {
+		if err := validateTarget(ws, ws.Attach, true); err != nil {
+			errs = append(errs, ValidationError{Field: "attach", Message: err.Error()})
+		}
+	}
+
 	return errs
 }
 
@@ -68,6 +74,7 @@ func validateSession(sess Session, seen map[string]bool, errs []ValidationError)
 }
 
 func validateWindows(sessionName string, windows []Window, errs []ValidationError) []ValidationError {
+	focused := 0
 	for i, window := range windows {
 		windowName := window.Name
 		if windowName == "" {
@@ -77,6 +84,19 @@ func validateWindows(sessionName string, windows []Window, errs []ValidationErro
 		if err := validateZoomedPanes(sessionName, windowName, window.Panes); err != nil {
 			errs = append(errs, *err)
 		}
+		if err := validateFocusedPanes(sessionName, windowName, window.Panes); err != nil {
+			errs = append(errs, *err)
+		}
+		if window.Focus {
+			focused++
+		}
+	}
+
+	if focused > 1 {
+		errs = append(errs, ValidationError{
+			Field:   fmt.Sprintf("session.%s", sessionName),
+			Message: fmt.Sprintf("has %d windows with focus=true (only one allowed per session)", focused),
+		})
 	}
 
 	return errs
@@ -97,3 +117,19 @@ func validateZoomedPanes(sessionName, windowName string, panes []Pane) *Validati
 	}
 	return nil
 }
+
+func validateFocusedPanes(sessionName, windowName string, panes []Pane) *ValidationError {
+	focused := 0
+	for _, pane := range panes {
+		if pane.Focus {
+			focused++
+		}
+	}
+	if focused > 1 {
+		return &ValidationError{
+			Field:   fmt.Sprintf("session.%s.window.%s", sessionName, windowName),
+			Message: fmt.Sprintf("has %d panes with focus=true (only one allowed per window)", focused),
+		}
+	}
+	return nil
+}
@@ -157,6 +157,91 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "multiple focused panes in one window",
+			workspace: &Workspace{
+				Sessions: []Session{
+					{
+						Name: "dev",
+						Windows: []Window{
+							{
+								Name: "editor",
+								Path: "/home",
+								Panes: []Pane{
+									{Path: "/home/user", Focus: true},
+									{Path: "/home/user", Focus: true},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "panes with focus=true",
+			wantErrCount:    1,
+		},
+		{
+			name: "multiple focused windows in one session",
+			workspace: &Workspace{
+				Sessions: []Session{
+					{
+						Name: "dev",
+						Windows: []Window{
+							{Name: "editor", Path: "/home", Focus: true},
+							{Name: "server", Path: "/home", Focus: true},
+						},
+					},
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "windows with focus=true",
+			wantErrCount:    1,
+		},
+		{
+			name: "attach to unknown window",
+			workspace: &Workspace{
+				Attach: "dev:logs",
+				Sessions: []Session{
+					{
+						Name:    "dev",
+						Windows: []Window{{Name: "editor", Path: "/home"}},
+					},
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "window \"logs\" not found",
+			wantErrCount:    1,
+		},
+		{
+			name: "attach by session prefix",
+			workspace: &Workspace{
+				Attach: "de:editor",
+				Sessions: []Session{
+					{
+						Name:    "dev",
+						Windows: []Window{{Name: "editor", Path: "/home"}},
+					},
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "session \"de\" not found",
+			wantErrCount:    1,
+		},
+		{
+			name: "attach by fuzzy window name",
+			workspace: &Workspace{
+				Attach: "dev:edr",
+				Sessions: []Session{
+					{
+						Name:    "dev",
+						Windows: []Window{{Name: "editor", Path: "/home"}},
+					},
+				},
+			},
+			wantErr:         true,
+			wantErrContains: "window \"edr\" not found",
+			wantErrCount:    1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2,6 +2,9 @@ package manifest
 
 type Workspace struct {
 	Sessions []Session `json:"sessions" yaml:"sessions"`
+	// Attach is where 'hetki start' attaches, as session[:window[.pane]]
+	// with the exact session and window names.
+	Attach string `json:"attach,omitempty" yaml:"attach,omitempty"`
 }
 
 type Session struct {
@@ -17,6 +20,7 @@ type Window struct {
 	Layout  string `json:"layout,omitempty" yaml:"layout,omitempty"`
 	Command string `json:"command,omitempty" yaml:"command,omitempty"`
 	Panes   []Pane `json:"panes,omitempty" yaml:"panes,omitempty"`
+	Focus   bool   `json:"focus,omitempty" yaml:"focus,omitempty"`
 }
 
 type Pane struct {
@@ -25,4 +29,5 @@ type Pane struct {
 	Split   string `json:"split,omitempty" yaml:"split,omitempty"`
 	Size    int    `json:"size,omitempty" yaml:"size,omitempty"`
 	Zoom    bool   `json:"zoom,omitempty" yaml:"zoom,omitempty"`
+	Focus   bool   `json:"focus,omitempty" yaml:"focus,omitempty"`
 }
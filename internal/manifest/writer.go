@@ -41,3 +41,35 @@ func Write(workspace *Workspace, path string) error {
 
 	return nil
 }
+
+// Copy copies the workspace file src to dst. When the two differ in format
+// the workspace is converted, otherwise the file is copied as is so that
+// comments and formatting survive. Paths are kept as written.
+func Copy(src, dst string) error {
+	src, dst = expandPath(src), expandPath(dst)
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	if isJSON(src) == isJSON(dst) {
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("create directory: %w", err)
+		}
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return fmt.Errorf("write file: %w", err)
+		}
+		return nil
+	}
+
+	raw, err := parse(data, filepath.Ext(src))
+	if err != nil {
+		return err
+	}
+	return Write(raw, dst)
+}
+
+func isJSON(path string) bool {
+	return filepath.Ext(path) == ".json"
+}
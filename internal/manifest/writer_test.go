@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const copySource = `# my project
+sessions:
+  - name: myapp
+    root: ~/code/myapp
+    windows:
+      - name: editor
+        path: src
+        command: vim
+`
+
+func TestCopySameFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "a.yaml")
+	dst := filepath.Join(tmpDir, "b.yml")
+	require.NoError(t, os.WriteFile(src, []byte(copySource), 0644))
+
+	require.NoError(t, Copy(src, dst))
+
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, copySource, string(data))
+}
+
+func TestCopyConvertsFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "a.yaml")
+	dst := filepath.Join(tmpDir, "b.json")
+	require.NoError(t, os.WriteFile(src, []byte(copySource), 0644))
+
+	require.NoError(t, Copy(src, dst))
+
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	var ws Workspace
+	require.NoError(t, json.Unmarshal(data, &ws))
+	require.Len(t, ws.Sessions, 1)
+	assert.Equal(t, "~/code/myapp", ws.Sessions[0].Root)
+	assert.Equal(t, "src", ws.Sessions[0].Windows[0].Path)
+
+	back := filepath.Join(tmpDir, "c.yaml")
+	require.NoError(t, Copy(dst, back))
+	loaded, err := NewFileLoader(back).Load()
+	require.NoError(t, err)
+	assert.Equal(t, "vim", loaded.Sessions[0].Windows[0].Command)
+}
+
+func TestCopyMissingSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	err := Copy(filepath.Join(tmpDir, "missing.yaml"), filepath.Join(tmpDir, "b.yaml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package picker
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+type keyKind int
+
+const (
+	keyRune keyKind = iota
+	keyEnter
+	keyCancel
+	keyBackspace
+	keyClearLine
+	keyDeleteWord
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+)
+
+type key struct {
+	kind keyKind
+	r    rune
+}
+
+// escapeKeys maps the escape sequences terminals send for the keys the
+// picker handles, in both normal and application cursor mode.
+var escapeKeys = map[string]keyKind{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+}
+
+var controlKeys = map[byte]keyKind{
+	'\r':   keyEnter,
+	'\n':   keyEnter,
+	0x03:   keyCancel,     // Ctrl-C
+	0x07:   keyCancel,     // Ctrl-G
+	0x7f:   keyBackspace,  // Backspace
+	0x08:   keyBackspace,  // Ctrl-H
+	0x15:   keyClearLine,  // Ctrl-U
+	0x17:   keyDeleteWord, // Ctrl-W
+	0x10:   keyUp,         // Ctrl-P
+	0x0b:   keyUp,         // Ctrl-K
+	0x0e:   keyDown,       // Ctrl-N
+	'\t':   keyDown,
+	0x02:   keyPageUp,   // Ctrl-B
+	0x06:   keyPageDown, // Ctrl-F
+	'\x1b': keyCancel,
+}
+
+// parseKeys splits what one read from the terminal returned into keys. A
+// lone escape is the Escape key; unknown escape sequences are dropped.
+func parseKeys(buf []byte) []key {
+	var keys []key
+	for len(buf) > 0 {
+		if buf[0] == '\x1b' && len(buf) > 1 {
+			n := escapeLength(buf)
+			if kind, ok := escapeKeys[string(buf[:n])]; ok {
+				keys = append(keys, key{kind: kind})
+			}
+			buf = buf[n:]
+			continue
+		}
+		if kind, ok := controlKeys[buf[0]]; ok {
+			keys = append(keys, key{kind: kind})
+			buf = buf[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRune(buf)
+		if unicode.IsPrint(r) {
+			keys = append(keys, key{kind: keyRune, r: r})
+		}
+		buf = buf[size:]
+	}
+	return keys
+}
+
+// escapeLength returns the length of the escape sequence at the start of
+// buf: ESC [ params final or ESC O final, otherwise ESC and one byte.
+func escapeLength(buf []byte) int {
+	switch buf[1] {
+	case '[':
+		if i := bytes.IndexFunc(buf[2:], func(r rune) bool { return r >= 0x40 && r <= 0x7e }); i >= 0 {
+			return i + 3
+		}
+		return len(buf)
+	case 'O':
+		return min(3, len(buf))
+	}
+	return 2
+}
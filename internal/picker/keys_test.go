@@ -0,0 +1,30 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []key
+	}{
+		{"text", "ab", []key{{kind: keyRune, r: 'a'}, {kind: keyRune, r: 'b'}}},
+		{"utf-8", "ä", []key{{kind: keyRune, r: 'ä'}}},
+		{"enter", "\r", []key{{kind: keyEnter}}},
+		{"escape", "\x1b", []key{{kind: keyCancel}}},
+		{"arrows", "\x1b[A\x1bOB", []key{{kind: keyUp}, {kind: keyDown}}},
+		{"page keys", "\x1b[5~\x1b[6~", []key{{kind: keyPageUp}, {kind: keyPageDown}}},
+		{"unknown sequence is dropped", "\x1b[1;5Cx", []key{{kind: keyRune, r: 'x'}}},
+		{"control keys", "\x7f\x15\x17\x03", []key{{kind: keyBackspace}, {kind: keyClearLine}, {kind: keyDeleteWord}, {kind: keyCancel}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseKeys([]byte(tt.input)))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package picker
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Match is an item that matched the query. Positions are the rune offsets
+// of the matched characters in the item label.
+type Match struct {
+	Index     int
+	Score     int
+	Positions []int
+}
+
+const (
+	scoreChar        = 16
+	bonusConsecutive = 8
+	bonusBoundary    = 10
+	maxGapPenalty    = 10
+)
+
+// Filter returns the items matching query, best first. The query is split
+// on spaces and every term has to match; a term matches when its characters
+// appear in order in the label. Terms are case-insensitive unless they
+// contain an upper case letter. With an empty query all items are returned
+// in their original order.
+func Filter(items []Item, query string) []Match {
+	terms := strings.Fields(query)
+	matches := make([]Match, 0, len(items))
+
+	for i, item := range items {
+		label := []rune(item.Label)
+		m := Match{Index: i}
+		ok := true
+		for _, term := range terms {
+			score, positions, found := matchTerm([]rune(term), label)
+			if !found {
+				ok = false
+				break
+			}
+			m.Score += score
+			m.Positions = append(m.Positions, positions...)
+		}
+		if ok {
+			slices.Sort(m.Positions)
+			m.Positions = slices.Compact(m.Positions)
+			matches = append(matches, m)
+		}
+	}
+
+	if len(terms) > 0 {
+		slices.SortStableFunc(matches, func(a, b Match) int {
+			if a.Score != b.Score {
+				return b.Score - a.Score
+			}
+			return len(items[a.Index].Label) - len(items[b.Index].Label)
+		})
+	}
+	return matches
+}
+
+// matchTerm finds pattern as a subsequence of text. Of the possible
+// matches it picks the shortest one ending at the first place the whole
+// pattern can be found, which keeps matched characters close together.
+func matchTerm(pattern, text []rune) (int, []int, bool) {
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+	fold := !slices.ContainsFunc(pattern, unicode.IsUpper)
+	eq := func(a, b rune) bool {
+		if fold {
+			return unicode.ToLower(a) == unicode.ToLower(b)
+		}
+		return a == b
+	}
+
+	pi, end := 0, -1
+	for i, r := range text {
+		if eq(r, pattern[pi]) {
+			pi++
+			if pi == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	pi, start := len(pattern)-1, 0
+	for i := end; i >= 0; i-- {
+		if eq(text[i], pattern[pi]) {
+			pi--
+			if pi < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	positions := make([]int, 0, len(pattern))
+	pi = 0
+	for i := start; i <= end && pi < len(pattern); i++ {
+		if eq(text[i], pattern[pi]) {
+			positions = append(positions, i)
+			pi++
+		}
+	}
+
+	return score(positions, text), positions, true
+}
+
+func score(positions []int, text []rune) int {
+	total := 0
+	for i, p := range positions {
+		total += scoreChar
+		if i > 0 {
+			if gap := p - positions[i-1] - 1; gap == 0 {
+				total += bonusConsecutive
+			} else {
+				total -= min(gap, maxGapPenalty)
+			}
+		}
+		if p == 0 || isBoundary(text[p-1], text[p]) {
+			total += bonusBoundary
+		}
+	}
+	return total
+}
+
+// isBoundary reports whether cur starts a word, e.g. after a separator or
+// at a camelCase hump.
+func isBoundary(prev, cur rune) bool {
+	if strings.ContainsRune(" /:._-", prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
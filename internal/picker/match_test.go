@@ -0,0 +1,97 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func labels(items []Item, matches []Match) []string {
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = items[m.Index].Label
+	}
+	return out
+}
+
+func TestFilter(t *testing.T) {
+	items := []Item{
+		{Label: "dotfiles"},
+		{Label: "work"},
+		{Label: "work:editor"},
+		{Label: "work:server"},
+		{Label: "api:editor"},
+		{Label: "WebApp"},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "empty query keeps order",
+			query:    "",
+			expected: []string{"dotfiles", "work", "work:editor", "work:server", "api:editor", "WebApp"},
+		},
+		{
+			name:     "shorter label wins a tie",
+			query:    "work",
+			expected: []string{"work", "work:editor", "work:server"},
+		},
+		{
+			name:     "consecutive characters rank first",
+			query:    "edit",
+			expected: []string{"api:editor", "work:editor"},
+		},
+		{
+			name:     "all terms must match",
+			query:    "wo ed",
+			expected: []string{"work:editor"},
+		},
+		{
+			name:     "word boundaries rank first",
+			query:    "we",
+			expected: []string{"WebApp", "work:editor", "work:server"},
+		},
+		{
+			name:     "upper case is matched exactly",
+			query:    "A",
+			expected: []string{"WebApp"},
+		},
+		{
+			name:     "no match",
+			query:    "xyz",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, labels(items, Filter(items, tt.query)))
+		})
+	}
+}
+
+func TestMatchTermPositions(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		positions []int
+		ok        bool
+	}{
+		{"abc", "abc", []int{0, 1, 2}, true},
+		{"ed", "work:editor", []int{5, 6}, true},
+		{"wre", "work:editor", []int{0, 2, 5}, true},
+		{"ab", "a-x-ab", []int{4, 5}, true},
+		{"ba", "ab", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.text, func(t *testing.T) {
+			_, positions, ok := matchTerm([]rune(tt.pattern), []rune(tt.text))
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.positions, positions)
+		})
+	}
+}
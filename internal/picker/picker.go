@@ -0,0 +1,310 @@
+// Package picker is a small fuzzy finder drawn on the terminal, so choosing
+// a session or workspace does not need an external tool like fzf.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Item is one entry in the picker. Only the label is matched against the
+// query; the detail is shown dimmed after it.
+type Item struct {
+	Label  string
+	Detail string
+	// Preview returns the text shown next to the list while the item is
+	// selected, at most height lines. It is called once per item and may
+	// be nil.
+	Preview func(width, height int) string
+}
+
+type Options struct {
+	Prompt  string
+	Preview bool
+}
+
+var ErrCancelled = errors.New("selection cancelled")
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[H"
+	exitScreen  = "\x1b[?1049l"
+
+	// The preview is only shown when both panels get a usable width.
+	minPreviewWidth = 60
+)
+
+// Run shows items on the terminal and returns the index of the chosen one,
+// or ErrCancelled when the user quits with Escape or Ctrl-C.
+func Run(items []Item, opts Options) (int, error) {
+	if len(items) == 0 {
+		return -1, errors.New("nothing to pick from")
+	}
+	if opts.Prompt == "" {
+		opts.Prompt = "> "
+	}
+
+	t, err := openTerminal()
+	if err != nil {
+		return -1, err
+	}
+	defer t.close()
+	if err := t.makeRaw(); err != nil {
+		return -1, err
+	}
+	fmt.Fprint(t.file, enterScreen)
+	defer fmt.Fprint(t.file, exitScreen)
+
+	input := make(chan []byte)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := t.file.Read(buf)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case input <- slices.Clone(buf[:n]):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	defer signal.Stop(resize)
+
+	s := newState(items, opts)
+	for {
+		width, height := t.size()
+		fmt.Fprint(t.file, s.render(width, height))
+
+		select {
+		case buf := <-input:
+			for _, k := range parseKeys(buf) {
+				switch s.handle(k, height-1) {
+				case actionPick:
+					return s.matches[s.cursor].Index, nil
+				case actionCancel:
+					return -1, ErrCancelled
+				}
+			}
+		case <-resize:
+			clear(s.previews)
+		case err := <-readErr:
+			return -1, fmt.Errorf("reading terminal: %w", err)
+		}
+	}
+}
+
+type action int
+
+const (
+	actionNone action = iota
+	actionPick
+	actionCancel
+)
+
+type state struct {
+	items    []Item
+	opts     Options
+	query    []rune
+	matches  []Match
+	cursor   int
+	offset   int
+	previews map[int]string
+}
+
+func newState(items []Item, opts Options) *state {
+	s := &state{items: items, opts: opts, previews: make(map[int]string)}
+	s.filter()
+	return s
+}
+
+func (s *state) filter() {
+	s.matches = Filter(s.items, string(s.query))
+	s.cursor, s.offset = 0, 0
+}
+
+func (s *state) move(delta int) {
+	s.cursor = max(0, min(s.cursor+delta, len(s.matches)-1))
+}
+
+func (s *state) handle(k key, pageSize int) action {
+	switch k.kind {
+	case keyRune:
+		s.query = append(s.query, k.r)
+		s.filter()
+	case keyBackspace:
+		if len(s.query) > 0 {
+			s.query = s.query[:len(s.query)-1]
+			s.filter()
+		}
+	case keyClearLine:
+		s.query = nil
+		s.filter()
+	case keyDeleteWord:
+		q := strings.TrimRightFunc(string(s.query), unicode.IsSpace)
+		i := strings.LastIndexFunc(q, unicode.IsSpace)
+		s.query = []rune(q[:i+1])
+		s.filter()
+	case keyUp:
+		s.move(-1)
+	case keyDown:
+		s.move(1)
+	case keyPageUp:
+		s.move(-max(pageSize, 1))
+	case keyPageDown:
+		s.move(max(pageSize, 1))
+	case keyEnter:
+		if len(s.matches) > 0 {
+			return actionPick
+		}
+	case keyCancel:
+		return actionCancel
+	}
+	return actionNone
+}
+
+// render draws the whole screen: the prompt and the list on the left and,
+// when there is room, the preview of the selected item on the right.
+func (s *state) render(width, height int) string {
+	listWidth, previewWidth := width, 0
+	if s.opts.Preview && width >= minPreviewWidth {
+		listWidth = width * 2 / 5
+		previewWidth = width - listWidth - 3
+	}
+
+	rows := max(height-1, 1)
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+rows {
+		s.offset = s.cursor - rows + 1
+	}
+
+	var preview []string
+	if previewWidth > 0 && len(s.matches) > 0 {
+		preview = strings.Split(s.preview(s.matches[s.cursor].Index, previewWidth, height), "\n")
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[?25l")
+	for row := range height {
+		fmt.Fprintf(&b, "\x1b[%d;1H", row+1)
+		switch {
+		case row == 0:
+			b.WriteString(s.renderPrompt(listWidth))
+		case row-1+s.offset < len(s.matches):
+			i := row - 1 + s.offset
+			b.WriteString(s.renderItem(s.matches[i], i == s.cursor, listWidth))
+		case row == 1 && len(s.matches) == 0:
+			b.WriteString(pad("\x1b[2m  no matches\x1b[0m", len("  no matches"), listWidth))
+		default:
+			b.WriteString(strings.Repeat(" ", listWidth))
+		}
+		if previewWidth > 0 {
+			b.WriteString(" \x1b[2m│\x1b[0m ")
+			if row < len(preview) {
+				b.WriteString(sanitize(preview[row], previewWidth))
+			}
+		}
+		b.WriteString("\x1b[K")
+	}
+
+	// Leave the cursor at the end of the query.
+	col := len([]rune(s.opts.Prompt)) + len(s.query) + 1
+	fmt.Fprintf(&b, "\x1b[1;%dH\x1b[?25h", min(col, listWidth))
+	return b.String()
+}
+
+func (s *state) renderPrompt(width int) string {
+	text := s.opts.Prompt + string(s.query)
+	count := fmt.Sprintf("%d/%d", len(s.matches), len(s.items))
+	n := len([]rune(text))
+	if n+len(count)+1 > width {
+		return pad(truncate(text, width), min(n, width), width)
+	}
+	return text + strings.Repeat(" ", width-n-len(count)) + "\x1b[2m" + count + "\x1b[0m"
+}
+
+func (s *state) renderItem(m Match, selected bool, width int) string {
+	var b strings.Builder
+	used := 2
+	if selected {
+		b.WriteString("\x1b[1;36m>\x1b[39m ")
+	} else {
+		b.WriteString("  ")
+	}
+
+	for i, r := range []rune(s.items[m.Index].Label) {
+		if used >= width {
+			break
+		}
+		if slices.Contains(m.Positions, i) {
+			b.WriteString("\x1b[32m" + string(r) + "\x1b[39m")
+		} else {
+			b.WriteRune(r)
+		}
+		used++
+	}
+
+	if detail := s.items[m.Index].Detail; detail != "" && used+3 < width {
+		detail = truncate(detail, width-used-2)
+		b.WriteString("  \x1b[2m" + detail + "\x1b[22m")
+		used += 2 + len([]rune(detail))
+	}
+
+	b.WriteString("\x1b[0m")
+	return pad(b.String(), used, width)
+}
+
+func (s *state) preview(index, width, height int) string {
+	if text, ok := s.previews[index]; ok {
+		return text
+	}
+	var text string
+	if fn := s.items[index].Preview; fn != nil {
+		text = fn(width, height)
+	}
+	s.previews[index] = text
+	return text
+}
+
+// sanitize cuts a line of preview text to width and drops control
+// characters that would move the cursor.
+func sanitize(line string, width int) string {
+	line = strings.ReplaceAll(line, "\t", "    ")
+	line = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, line)
+	return truncate(line, width)
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:max(width, 0)])
+}
+
+// pad fills s, which takes up used columns on screen, with spaces to width.
+func pad(s string, used, width int) string {
+	if used >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-used)
+}
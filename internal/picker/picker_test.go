@@ -0,0 +1,65 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func typeKeys(s *state, input string) action {
+	var last action
+	for _, k := range parseKeys([]byte(input)) {
+		last = s.handle(k, 10)
+	}
+	return last
+}
+
+func TestStateHandle(t *testing.T) {
+	items := []Item{{Label: "api"}, {Label: "work"}, {Label: "web"}}
+
+	t.Run("typing filters and resets the cursor", func(t *testing.T) {
+		s := newState(items, Options{})
+		typeKeys(s, "\x1b[B\x1b[B")
+		assert.Equal(t, 2, s.cursor)
+
+		typeKeys(s, "w")
+		assert.Equal(t, "w", string(s.query))
+		assert.Len(t, s.matches, 2)
+		assert.Equal(t, 0, s.cursor)
+	})
+
+	t.Run("cursor stays within the matches", func(t *testing.T) {
+		s := newState(items, Options{})
+		typeKeys(s, "\x1b[A")
+		assert.Equal(t, 0, s.cursor)
+		typeKeys(s, "\x1b[6~")
+		assert.Equal(t, 2, s.cursor)
+	})
+
+	t.Run("editing the query", func(t *testing.T) {
+		s := newState(items, Options{})
+		typeKeys(s, "we ap\x17")
+		assert.Equal(t, "we ", string(s.query))
+		typeKeys(s, "\x7f\x7f")
+		assert.Equal(t, "w", string(s.query))
+		typeKeys(s, "\x15")
+		assert.Empty(t, s.query)
+		assert.Len(t, s.matches, 3)
+	})
+
+	t.Run("enter picks the selected match", func(t *testing.T) {
+		s := newState(items, Options{})
+		assert.Equal(t, actionPick, typeKeys(s, "web\r"))
+		assert.Equal(t, 2, s.matches[s.cursor].Index)
+	})
+
+	t.Run("enter without matches does nothing", func(t *testing.T) {
+		s := newState(items, Options{})
+		assert.Equal(t, actionNone, typeKeys(s, "zzz\r"))
+	})
+
+	t.Run("escape cancels", func(t *testing.T) {
+		s := newState(items, Options{})
+		assert.Equal(t, actionCancel, typeKeys(s, "\x1b"))
+	})
+}
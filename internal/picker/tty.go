@@ -0,0 +1,94 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package picker
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminal is the controlling terminal, opened directly so the picker works
+// when stdin and stdout are redirected.
+type terminal struct {
+	file  *os.File
+	saved *unix.Termios
+}
+
+func openTerminal() (*terminal, error) {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening terminal: %w", err)
+	}
+	return &terminal{file: f}, nil
+}
+
+// control runs fn with the file descriptor. Unlike Fd it leaves the file in
+// non-blocking mode, so close interrupts a pending read instead of leaving
+// it to steal input from whatever runs in the terminal next.
+func (t *terminal) control(fn func(fd int) error) error {
+	conn, err := t.file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var fnErr error
+	if err := conn.Control(func(fd uintptr) { fnErr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// makeRaw turns off line buffering, echo and signal keys, the same way
+// cfmakeraw does.
+func (t *terminal) makeRaw() error {
+	return t.control(func(fd int) error {
+		termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+		if err != nil {
+			return fmt.Errorf("reading terminal mode: %w", err)
+		}
+		saved := *termios
+
+		termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+		termios.Oflag &^= unix.OPOST
+		termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+		termios.Cflag &^= unix.CSIZE | unix.PARENB
+		termios.Cflag |= unix.CS8
+		termios.Cc[unix.VMIN] = 1
+		termios.Cc[unix.VTIME] = 0
+
+		if err := unix.IoctlSetTermios(fd, ioctlSetTermios, termios); err != nil {
+			return fmt.Errorf("setting terminal mode: %w", err)
+		}
+		t.saved = &saved
+		return nil
+	})
+}
+
+func (t *terminal) size() (width, height int) {
+	width, height = 80, 24
+	t.control(func(fd int) error {
+		ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+		if err == nil && ws.Col > 0 && ws.Row > 0 {
+			width, height = int(ws.Col), int(ws.Row)
+		}
+		return err
+	})
+	return width, height
+}
+
+func (t *terminal) close() {
+	if t.saved != nil {
+		t.control(func(fd int) error {
+			return unix.IoctlSetTermios(fd, ioctlSetTermios, t.saved)
+		})
+	}
+	t.file.Close()
+}
+
+// notifyResize sends to c whenever the terminal changes size.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
@@ -0,0 +1,33 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package picker
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// terminal is unavailable here: raw mode is only implemented through the
+// termios ioctls of Linux, macOS and the BSDs.
+type terminal struct {
+	file *os.File
+}
+
+func openTerminal() (*terminal, error) {
+	return nil, fmt.Errorf("the picker is not supported on %s", runtime.GOOS)
+}
+
+func (t *terminal) makeRaw() error {
+	return fmt.Errorf("the picker is not supported on %s", runtime.GOOS)
+}
+
+func (t *terminal) size() (width, height int) {
+	return 80, 24
+}
+
+func (t *terminal) close() {
+	t.file.Close()
+}
+
+func notifyResize(c chan<- os.Signal) {}
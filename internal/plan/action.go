@@ -8,6 +8,9 @@ import (
 type Action interface {
 	Comment() string
 	Validate() error
+	// Inverse returns the action that undoes this one, or nil if it cannot
+	// be undone on its own.
+	Inverse() Action
 }
 
 type CreateSessionAction struct {
@@ -27,6 +30,10 @@ func (a CreateSessionAction) Validate() error {
 	return nil
 }
 
+func (a CreateSessionAction) Inverse() Action {
+	return KillSessionAction{Name: a.Name}
+}
+
 type CreateWindowAction struct {
 	Session string
 	Name    string
@@ -44,6 +51,10 @@ func (a CreateWindowAction) Validate() error {
 	return nil
 }
 
+func (a CreateWindowAction) Inverse() Action {
+	return KillWindowAction{Session: a.Session, Window: a.Name}
+}
+
 type SplitPaneAction struct {
 	Session string
 	Window  string
@@ -61,6 +72,10 @@ func (a SplitPaneAction) Validate() error {
 	return nil
 }
 
+func (a SplitPaneAction) Inverse() Action {
+	return nil
+}
+
 type SendKeysAction struct {
 	Session string
 	Window  string
@@ -79,6 +94,10 @@ func (a SendKeysAction) Validate() error {
 	return nil
 }
 
+func (a SendKeysAction) Inverse() Action {
+	return nil
+}
+
 type KillSessionAction struct {
 	Name string
 }
@@ -94,6 +113,10 @@ func (a KillSessionAction) Validate() error {
 	return nil
 }
 
+func (a KillSessionAction) Inverse() Action {
+	return nil
+}
+
 type KillWindowAction struct {
 	Session string
 	Window  string
@@ -110,6 +133,10 @@ func (a KillWindowAction) Validate() error {
 	return nil
 }
 
+func (a KillWindowAction) Inverse() Action {
+	return nil
+}
+
 type SelectLayoutAction struct {
 	Session string
 	Window  string
@@ -127,6 +154,10 @@ func (a SelectLayoutAction) Validate() error {
 	return nil
 }
 
+func (a SelectLayoutAction) Inverse() Action {
+	return nil
+}
+
 type ZoomPaneAction struct {
 	Session string
 	Window  string
@@ -143,3 +174,75 @@ func (a ZoomPaneAction) Validate() error {
 	}
 	return nil
 }
+
+func (a ZoomPaneAction) Inverse() Action {
+	return nil
+}
+
+// SelectWindowAction makes a window the active one in its session.
+type SelectWindowAction struct {
+	Session string
+	Window  string
+}
+
+func (a SelectWindowAction) Comment() string {
+	return fmt.Sprintf("# Select window: %s:%s", a.Session, a.Window)
+}
+
+func (a SelectWindowAction) Validate() error {
+	if a.Session == "" || a.Window == "" {
+		return errors.New("select window session and window cannot be empty")
+	}
+	return nil
+}
+
+func (a SelectWindowAction) Inverse() Action {
+	return nil
+}
+
+// SelectPaneAction makes a pane the active one in its window.
+type SelectPaneAction struct {
+	Session string
+	Window  string
+	Pane    int
+}
+
+func (a SelectPaneAction) Comment() string {
+	return fmt.Sprintf("# Select pane: %s:%s.%d", a.Session, a.Window, a.Pane)
+}
+
+func (a SelectPaneAction) Validate() error {
+	if a.Session == "" || a.Window == "" {
+		return errors.New("select pane session and window cannot be empty")
+	}
+	return nil
+}
+
+func (a SelectPaneAction) Inverse() Action {
+	return nil
+}
+
+// RestoreScrollbackAction replays saved pane history from File into a pane
+// that was just created, before any command is sent to it.
+type RestoreScrollbackAction struct {
+	Session string
+	Window  string
+	Pane    int
+	Path    string
+	File    string
+}
+
+func (a RestoreScrollbackAction) Comment() string {
+	return fmt.Sprintf("# Restore scrollback: %s:%s", a.Session, a.Window)
+}
+
+func (a RestoreScrollbackAction) Validate() error {
+	if a.Session == "" || a.Window == "" || a.File == "" {
+		return errors.New("restore scrollback session, window, and file cannot be empty")
+	}
+	return nil
+}
+
+func (a RestoreScrollbackAction) Inverse() Action {
+	return nil
+}
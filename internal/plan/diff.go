@@ -25,11 +25,14 @@ type Window struct {
 	Name   string
 	Path   string
 	Layout string
+	Focus  bool
 	Panes  []Pane
 }
 
 type Pane struct {
-	Path    string
-	Command string
-	Zoom    bool
+	Path       string
+	Command    string
+	Zoom       bool
+	Focus      bool
+	Scrollback string
 }
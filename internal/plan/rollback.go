@@ -0,0 +1,60 @@
+package plan
+
+// Rollback builds a plan that undoes completed actions in reverse order. It
+// also returns the completed actions that could not be undone. Actions that
+// only touch a session or window removed by the rollback are dropped, since
+// removing it undoes them too.
+func Rollback(completed []Action) (*Plan, []Action) {
+	sessions := make(map[string]bool)
+	windows := make(map[string]bool) // key: session|name
+	for _, a := range completed {
+		switch a := a.(type) {
+		case CreateSessionAction:
+			sessions[a.Name] = true
+		case CreateWindowAction:
+			windows[a.Session+"|"+a.Name] = true
+		}
+	}
+
+	plan := &Plan{Actions: []Action{}}
+	var irreversible []Action
+
+	for i := len(completed) - 1; i >= 0; i-- {
+		a := completed[i]
+		if removedByRollback(a, sessions, windows) {
+			continue
+		}
+		if inverse := a.Inverse(); inverse != nil {
+			plan.Actions = append(plan.Actions, inverse)
+		} else {
+			irreversible = append(irreversible, a)
+		}
+	}
+
+	return plan, irreversible
+}
+
+func removedByRollback(a Action, sessions, windows map[string]bool) bool {
+	var session, window string
+	switch a := a.(type) {
+	case CreateWindowAction:
+		return sessions[a.Session]
+	case SplitPaneAction:
+		session, window = a.Session, a.Window
+	case SendKeysAction:
+		session, window = a.Session, a.Window
+	case SelectLayoutAction:
+		session, window = a.Session, a.Window
+	case ZoomPaneAction:
+		session, window = a.Session, a.Window
+	case RestoreScrollbackAction:
+		session, window = a.Session, a.Window
+	case SelectWindowAction:
+		session, window = a.Session, a.Window
+	case SelectPaneAction:
+		session, window = a.Session, a.Window
+	default:
+		return false
+	}
+	return sessions[session] || windows[session+"|"+window]
+}
@@ -0,0 +1,76 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollback(t *testing.T) {
+	tests := []struct {
+		name             string
+		completed        []Action
+		want             []Action
+		wantIrreversible []Action
+	}{
+		{
+			name:      "nothing completed",
+			completed: nil,
+			want:      []Action{},
+		},
+		{
+			name: "created session is killed with everything inside it",
+			completed: []Action{
+				CreateSessionAction{Name: "dev", WindowName: "editor", Path: "~/code"},
+				SplitPaneAction{Session: "dev", Window: "editor", Path: "~/code"},
+				SendKeysAction{Session: "dev", Window: "editor", Pane: 1, Command: "vim"},
+				CreateWindowAction{Session: "dev", Name: "server", Path: "~/api"},
+			},
+			want: []Action{KillSessionAction{Name: "dev"}},
+		},
+		{
+			name: "windows are killed in reverse order",
+			completed: []Action{
+				CreateWindowAction{Session: "dev", Name: "a", Path: "~/a"},
+				SelectLayoutAction{Session: "dev", Window: "a", Layout: "tiled"},
+				CreateWindowAction{Session: "dev", Name: "b", Path: "~/b"},
+				ZoomPaneAction{Session: "dev", Window: "b", Pane: 0},
+				SelectPaneAction{Session: "dev", Window: "b", Pane: 0},
+				SelectWindowAction{Session: "dev", Window: "b"},
+			},
+			want: []Action{
+				KillWindowAction{Session: "dev", Window: "b"},
+				KillWindowAction{Session: "dev", Window: "a"},
+			},
+		},
+		{
+			name: "kills cannot be undone",
+			completed: []Action{
+				KillSessionAction{Name: "old"},
+				KillWindowAction{Session: "dev", Window: "editor"},
+				CreateWindowAction{Session: "dev", Name: "editor", Path: "~/new"},
+			},
+			want: []Action{KillWindowAction{Session: "dev", Window: "editor"}},
+			wantIrreversible: []Action{
+				KillWindowAction{Session: "dev", Window: "editor"},
+				KillSessionAction{Name: "old"},
+			},
+		},
+		{
+			name: "commands in existing windows cannot be undone",
+			completed: []Action{
+				SendKeysAction{Session: "dev", Window: "editor", Command: "make"},
+			},
+			want:             []Action{},
+			wantIrreversible: []Action{SendKeysAction{Session: "dev", Window: "editor", Command: "make"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, irreversible := Rollback(tt.completed)
+			assert.Equal(t, tt.want, plan.Actions)
+			assert.Equal(t, tt.wantIrreversible, irreversible)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package plan
+
+import "strings"
+
+// Step is a backend-independent, serializable description of an action.
+type Step struct {
+	Type    string `json:"type" yaml:"type"`
+	Session string `json:"session" yaml:"session"`
+	Window  string `json:"window,omitempty" yaml:"window,omitempty"`
+	Pane    *int   `json:"pane,omitempty" yaml:"pane,omitempty"`
+	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	Layout  string `json:"layout,omitempty" yaml:"layout,omitempty"`
+	File    string `json:"file,omitempty" yaml:"file,omitempty"`
+	Comment string `json:"comment" yaml:"comment"`
+}
+
+func (p *Plan) Steps() []Step {
+	steps := make([]Step, len(p.Actions))
+	for i, a := range p.Actions {
+		steps[i] = StepOf(a)
+	}
+	return steps
+}
+
+func StepOf(a Action) Step {
+	step := Step{Comment: strings.TrimPrefix(a.Comment(), "# ")}
+
+	switch a := a.(type) {
+	case CreateSessionAction:
+		step.Type, step.Session, step.Window, step.Path = "create_session", a.Name, a.WindowName, a.Path
+	case CreateWindowAction:
+		step.Type, step.Session, step.Window, step.Path = "create_window", a.Session, a.Name, a.Path
+	case SplitPaneAction:
+		step.Type, step.Session, step.Window, step.Path = "split_pane", a.Session, a.Window, a.Path
+	case SendKeysAction:
+		step.Type, step.Session, step.Window, step.Pane, step.Command = "send_keys", a.Session, a.Window, &a.Pane, a.Command
+	case SelectLayoutAction:
+		step.Type, step.Session, step.Window, step.Layout = "select_layout", a.Session, a.Window, a.Layout
+	case ZoomPaneAction:
+		step.Type, step.Session, step.Window, step.Pane = "zoom_pane", a.Session, a.Window, &a.Pane
+	case SelectWindowAction:
+		step.Type, step.Session, step.Window = "select_window", a.Session, a.Window
+	case SelectPaneAction:
+		step.Type, step.Session, step.Window, step.Pane = "select_pane", a.Session, a.Window, &a.Pane
+	case RestoreScrollbackAction:
+		step.Type, step.Session, step.Window, step.Pane, step.Path, step.File = "restore_scrollback", a.Session, a.Window, &a.Pane, a.Path, a.File
+	case KillSessionAction:
+		step.Type, step.Session = "kill_session", a.Name
+	case KillWindowAction:
+		step.Type, step.Session, step.Window = "kill_window", a.Session, a.Window
+	default:
+		step.Type = "unknown"
+	}
+
+	return step
+}
@@ -0,0 +1,30 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSteps(t *testing.T) {
+	pane := 1
+	p := &Plan{Actions: []Action{
+		CreateSessionAction{Name: "dev", WindowName: "editor", Path: "~/code"},
+		SendKeysAction{Session: "dev", Window: "editor", Pane: 1, Command: "vim"},
+		SelectLayoutAction{Session: "dev", Window: "editor", Layout: "tiled"},
+		KillWindowAction{Session: "dev", Window: "old"},
+		SelectPaneAction{Session: "dev", Window: "editor", Pane: 1},
+		SelectWindowAction{Session: "dev", Window: "editor"},
+	}}
+
+	want := []Step{
+		{Type: "create_session", Session: "dev", Window: "editor", Path: "~/code", Comment: "Create session: dev"},
+		{Type: "send_keys", Session: "dev", Window: "editor", Pane: &pane, Command: "vim", Comment: "Send command to: dev:editor"},
+		{Type: "select_layout", Session: "dev", Window: "editor", Layout: "tiled", Comment: "Set layout: dev:editor -> tiled"},
+		{Type: "kill_window", Session: "dev", Window: "old", Comment: "Kill window: dev:old"},
+		{Type: "select_pane", Session: "dev", Window: "editor", Pane: &pane, Comment: "Select pane: dev:editor.1"},
+		{Type: "select_window", Session: "dev", Window: "editor", Comment: "Select window: dev:editor"},
+	}
+
+	assert.Equal(t, want, p.Steps())
+}
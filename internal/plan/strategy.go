@@ -1,5 +1,7 @@
 package plan
 
+import "slices"
+
 type Strategy interface {
 	Plan(diff Diff) *Plan
 }
@@ -39,12 +41,17 @@ func killExtra(plan *Plan, diff Diff) {
 
 func recreateMismatched(plan *Plan, diff Diff) {
 	for sessionName, windowDiff := range diff.Windows {
-		for _, mismatch := range windowDiff.Mismatched {
+		desired := make([]Window, len(windowDiff.Mismatched))
+		for i, mismatch := range windowDiff.Mismatched {
+			desired[i] = mismatch.Desired
+		}
+		focused := focusedWindow(desired)
+		for i, mismatch := range windowDiff.Mismatched {
 			plan.Actions = append(plan.Actions, KillWindowAction{
 				Session: sessionName,
 				Window:  mismatch.Actual.Name,
 			})
-			createWindow(plan, sessionName, mismatch.Desired)
+			createWindow(plan, sessionName, mismatch.Desired, i == focused)
 		}
 	}
 }
@@ -55,8 +62,9 @@ func createMissing(plan *Plan, diff Diff) {
 	}
 
 	for sessionName, windowDiff := range diff.Windows {
-		for _, window := range windowDiff.Missing {
-			createWindow(plan, sessionName, window)
+		focused := focusedWindow(windowDiff.Missing)
+		for i, window := range windowDiff.Missing {
+			createWindow(plan, sessionName, window, i == focused)
 		}
 	}
 }
@@ -66,6 +74,7 @@ func createSession(plan *Plan, session Session) {
 		return
 	}
 
+	focused := focusedWindow(session.Windows)
 	firstWindow := session.Windows[0]
 	plan.Actions = append(plan.Actions, CreateSessionAction{
 		Name:       session.Name,
@@ -73,19 +82,58 @@ func createSession(plan *Plan, session Session) {
 		Path:       firstWindow.Path,
 	})
 	addPanesAndCommands(plan, session.Name, firstWindow)
+	selectFocus(plan, session.Name, firstWindow, focused == 0)
 
-	for _, window := range session.Windows[1:] {
-		createWindow(plan, session.Name, window)
+	for i, window := range session.Windows[1:] {
+		createWindow(plan, session.Name, window, focused == i+1)
+	}
+}
+
+// focusedWindow returns the position of the window to select among windows
+// created together: the first with focus, else the first with a focused
+// pane, or -1 for none.
+func focusedWindow(windows []Window) int {
+	paneFocused := -1
+	for i, window := range windows {
+		if window.Focus {
+			return i
+		}
+		if paneFocused < 0 && slices.ContainsFunc(window.Panes, func(p Pane) bool { return p.Focus }) {
+			paneFocused = i
+		}
+	}
+	return paneFocused
+}
+
+// selectFocus selects the focused panes of a window right after creating
+// it, and the window itself when selectWindow is set. Backends create the
+// later windows of the session without selecting them, so the choice sticks.
+func selectFocus(plan *Plan, sessionName string, window Window, selectWindow bool) {
+	for i, pane := range window.Panes {
+		if pane.Focus {
+			plan.Actions = append(plan.Actions, SelectPaneAction{
+				Session: sessionName,
+				Window:  window.Name,
+				Pane:    i,
+			})
+		}
+	}
+	if selectWindow {
+		plan.Actions = append(plan.Actions, SelectWindowAction{
+			Session: sessionName,
+			Window:  window.Name,
+		})
 	}
 }
 
-func createWindow(plan *Plan, sessionName string, window Window) {
+func createWindow(plan *Plan, sessionName string, window Window, focus bool) {
 	plan.Actions = append(plan.Actions, CreateWindowAction{
 		Session: sessionName,
 		Name:    window.Name,
 		Path:    window.Path,
 	})
 	addPanesAndCommands(plan, sessionName, window)
+	selectFocus(plan, sessionName, window, focus)
 }
 
 func addPanesAndCommands(plan *Plan, sessionName string, window Window) {
@@ -108,6 +156,15 @@ func addPanesAndCommands(plan *Plan, sessionName string, window Window) {
 	}
 
 	for i, pane := range window.Panes {
+		if pane.Scrollback != "" {
+			plan.Actions = append(plan.Actions, RestoreScrollbackAction{
+				Session: sessionName,
+				Window:  window.Name,
+				Pane:    i,
+				Path:    pane.Path,
+				File:    pane.Scrollback,
+			})
+		}
 		if pane.Command != "" {
 			plan.Actions = append(plan.Actions, SendKeysAction{
 				Session: sessionName,
@@ -32,6 +32,71 @@ func TestMergeStrategyPlan(t *testing.T) {
 			},
 			want: []Action{CreateWindowAction{Session: "dev", Name: "server", Path: "~/api"}},
 		},
+		{
+			name: "restores scrollback before commands",
+			diff: Diff{
+				Sessions: ItemDiff[Session]{},
+				Windows: map[string]ItemDiff[Window]{
+					"dev": {Missing: []Window{{Name: "server", Path: "~/api", Panes: []Pane{
+						{Path: "~/api", Command: "npm start", Scrollback: "/tmp/dev/1.0.txt"},
+					}}}},
+				},
+			},
+			want: []Action{
+				CreateWindowAction{Session: "dev", Name: "server", Path: "~/api"},
+				RestoreScrollbackAction{Session: "dev", Window: "server", Pane: 0, Path: "~/api", File: "/tmp/dev/1.0.txt"},
+				SendKeysAction{Session: "dev", Window: "server", Pane: 0, Command: "npm start"},
+			},
+		},
+		{
+			name: "selects focus right after creating each window",
+			diff: Diff{
+				Sessions: ItemDiff[Session]{
+					Missing: []Session{{Name: "dev", Windows: []Window{
+						{Name: "editor", Path: "~/code", Focus: true},
+						{Name: "server", Path: "~/api", Panes: []Pane{{Path: "~/api", Focus: true}, {Path: "~/api"}}},
+					}}},
+				},
+				Windows: make(map[string]ItemDiff[Window]),
+			},
+			want: []Action{
+				CreateSessionAction{Name: "dev", WindowName: "editor", Path: "~/code"},
+				SelectWindowAction{Session: "dev", Window: "editor"},
+				CreateWindowAction{Session: "dev", Name: "server", Path: "~/api"},
+				SplitPaneAction{Session: "dev", Window: "server", Path: "~/api"},
+				SelectPaneAction{Session: "dev", Window: "server", Pane: 0},
+			},
+		},
+		{
+			name: "selects the focused one of windows with the same name",
+			diff: Diff{
+				Sessions: ItemDiff[Session]{},
+				Windows: map[string]ItemDiff[Window]{
+					"dev": {Missing: []Window{{Name: "main"}, {Name: "main", Focus: true}, {Name: "v1.2"}}},
+				},
+			},
+			want: []Action{
+				CreateWindowAction{Session: "dev", Name: "main"},
+				CreateWindowAction{Session: "dev", Name: "main"},
+				SelectWindowAction{Session: "dev", Window: "main"},
+				CreateWindowAction{Session: "dev", Name: "v1.2"},
+			},
+		},
+		{
+			name: "focused pane selects its window",
+			diff: Diff{
+				Sessions: ItemDiff[Session]{},
+				Windows: map[string]ItemDiff[Window]{
+					"dev": {Missing: []Window{{Name: "server", Path: "~/api", Panes: []Pane{{Path: "~/api"}, {Path: "~/api", Focus: true}}}}},
+				},
+			},
+			want: []Action{
+				CreateWindowAction{Session: "dev", Name: "server", Path: "~/api"},
+				SplitPaneAction{Session: "dev", Window: "server", Path: "~/api"},
+				SelectPaneAction{Session: "dev", Window: "server", Pane: 1},
+				SelectWindowAction{Session: "dev", Window: "server"},
+			},
+		},
 		{
 			name: "ignores extra",
 			diff: Diff{
@@ -0,0 +1,88 @@
+package proc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxDepth = 4
+
+// CommandLine returns the full command line of the process called name that
+// runs under pid, checking pid itself and then its descendants. It reads
+// /proc and reports false on systems without it.
+func CommandLine(pid int, name string) (string, bool) {
+	if pid <= 0 || name == "" {
+		return "", false
+	}
+
+	queue := []int{pid}
+	for depth := 0; depth <= maxDepth && len(queue) > 0; depth++ {
+		var next []int
+		for _, p := range queue {
+			args := cmdline(p)
+			if len(args) > 0 && Name(args[0]) == name {
+				return ShellJoin(args), true
+			}
+			next = append(next, children(p)...)
+		}
+		queue = next
+	}
+	return "", false
+}
+
+// Name returns the program name for argv0 the way tmux reports it in
+// pane_current_command: the base name without a login shell's leading dash.
+func Name(argv0 string) string {
+	return strings.TrimPrefix(filepath.Base(argv0), "-")
+}
+
+// ShellJoin joins args into a command line a shell would split back into
+// the same arguments.
+func ShellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shellQuote(arg string) string {
+	if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:@%=+,", r))
+	}) == -1 {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+func cmdline(pid int) []string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+}
+
+func children(pid int) []int {
+	tasks, err := filepath.Glob(fmt.Sprintf("/proc/%d/task/*/children", pid))
+	if err != nil {
+		return nil
+	}
+
+	var pids []int
+	for _, task := range tasks {
+		data, err := os.ReadFile(task)
+		if err != nil {
+			continue
+		}
+		for _, field := range strings.Fields(string(data)) {
+			var child int
+			if _, err := fmt.Sscanf(field, "%d", &child); err == nil {
+				pids = append(pids, child)
+			}
+		}
+	}
+	return pids
+}
@@ -0,0 +1,47 @@
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandLine(t *testing.T) {
+	if _, err := os.Stat("/proc/self/cmdline"); err != nil {
+		t.Skip("no /proc filesystem")
+	}
+
+	t.Run("finds a child process", func(t *testing.T) {
+		sleep := exec.Command("sleep", "30")
+		require.NoError(t, sleep.Start())
+		defer sleep.Process.Kill()
+
+		got, ok := CommandLine(os.Getpid(), "sleep")
+		assert.True(t, ok)
+		assert.Equal(t, "sleep 30", got)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		_, ok := CommandLine(os.Getpid(), "definitely-not-running")
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid pid", func(t *testing.T) {
+		_, ok := CommandLine(0, "sleep")
+		assert.False(t, ok)
+	})
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "zsh", Name("-zsh"))
+	assert.Equal(t, "vim", Name("/usr/bin/vim"))
+	assert.Equal(t, "node", Name("node"))
+}
+
+func TestShellJoin(t *testing.T) {
+	assert.Equal(t, "npm run dev", ShellJoin([]string{"npm", "run", "dev"}))
+	assert.Equal(t, "grep 'a b' 'it'\\''s'", ShellJoin([]string{"grep", "a b", "it's"}))
+}
@@ -0,0 +1,180 @@
+// Package scaffold proposes workspace manifests for new projects, either
+// from a named template or by looking at the files in a project directory.
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/MSmaili/hetki/internal/manifest"
+)
+
+// Template proposes windows for one kind of project. Detect reports whether
+// a directory holds such a project.
+type Template struct {
+	Name    string
+	Detect  func(dir string) bool
+	Windows func(dir string) []manifest.Window
+}
+
+var templates = []Template{
+	{
+		Name:   "go",
+		Detect: hasFile("go.mod"),
+		Windows: func(string) []manifest.Window {
+			return []manifest.Window{{Name: "test", Command: "go test ./..."}}
+		},
+	},
+	{
+		Name:    "node",
+		Detect:  hasFile("package.json"),
+		Windows: nodeWindows,
+	},
+	{
+		Name:   "rust",
+		Detect: hasFile("Cargo.toml"),
+		Windows: func(string) []manifest.Window {
+			return []manifest.Window{{Name: "test", Command: "cargo test"}}
+		},
+	},
+	{
+		Name:   "python",
+		Detect: hasAnyFile("pyproject.toml", "requirements.txt", "setup.py"),
+		Windows: func(string) []manifest.Window {
+			return []manifest.Window{{Name: "test", Command: "pytest"}}
+		},
+	},
+	{
+		Name:   "make",
+		Detect: hasFile("Makefile"),
+		Windows: func(string) []manifest.Window {
+			return []manifest.Window{{Name: "build", Command: "make"}}
+		},
+	},
+	{
+		Name:    "blank",
+		Detect:  func(string) bool { return false },
+		Windows: func(string) []manifest.Window { return nil },
+	},
+}
+
+// Names returns the names of the built-in templates.
+func Names() []string {
+	names := make([]string, len(templates))
+	for i, t := range templates {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Detect returns the templates matching the project in dir.
+func Detect(dir string) []string {
+	var found []string
+	for _, t := range templates {
+		if t.Detect(dir) {
+			found = append(found, t.Name)
+		}
+	}
+	return found
+}
+
+// New builds a workspace with one session called name rooted at root. dir
+// is the project directory the templates look at, which is usually root
+// before it is made relative. Windows proposed by several templates are
+// only added once. Every workspace starts with an editor window and ends
+// with a shell.
+func New(name, root, dir string, templateNames []string) (*manifest.Workspace, error) {
+	windows := []manifest.Window{{Name: "editor", Command: editorCommand}}
+
+	for _, tn := range templateNames {
+		t, err := lookup(tn)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range t.Windows(dir) {
+			if !slices.ContainsFunc(windows, func(existing manifest.Window) bool { return existing.Name == w.Name }) {
+				windows = append(windows, w)
+			}
+		}
+	}
+
+	windows = append(windows, manifest.Window{Name: "shell"})
+
+	return &manifest.Workspace{Sessions: []manifest.Session{{
+		Name:    name,
+		Root:    root,
+		Windows: windows,
+	}}}, nil
+}
+
+func lookup(name string) (Template, error) {
+	for _, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Template{}, fmt.Errorf("unknown template %q\nAvailable templates: %s", name, strings.Join(Names(), ", "))
+}
+
+// editorCommand opens the editor of whoever starts the workspace. The pane's
+// shell expands it, so the file does not depend on who created it.
+const editorCommand = "${EDITOR:-vi}"
+
+// nodeWindows proposes a server and test window from the scripts in
+// package.json, run with the package manager whose lockfile is present.
+func nodeWindows(dir string) []manifest.Window {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+
+	pm := "npm"
+	switch {
+	case hasFile("pnpm-lock.yaml")(dir):
+		pm = "pnpm"
+	case hasFile("yarn.lock")(dir):
+		pm = "yarn"
+	case hasFile("bun.lockb")(dir):
+		pm = "bun"
+	}
+
+	var windows []manifest.Window
+	for _, script := range []string{"dev", "start"} {
+		if _, ok := pkg.Scripts[script]; ok {
+			windows = append(windows, manifest.Window{Name: "server", Command: pm + " run " + script})
+			break
+		}
+	}
+	if _, ok := pkg.Scripts["test"]; ok {
+		windows = append(windows, manifest.Window{Name: "test", Command: pm + " test"})
+	}
+	return windows
+}
+
+func hasFile(name string) func(dir string) bool {
+	return func(dir string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+}
+
+func hasAnyFile(names ...string) func(dir string) bool {
+	return func(dir string) bool {
+		for _, name := range names {
+			if hasFile(name)(dir) {
+				return true
+			}
+		}
+		return false
+	}
+}
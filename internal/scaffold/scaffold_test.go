@@ -0,0 +1,125 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected []string
+	}{
+		{
+			name:     "empty directory",
+			expected: nil,
+		},
+		{
+			name:     "go module",
+			files:    map[string]string{"go.mod": "module x"},
+			expected: []string{"go"},
+		},
+		{
+			name:     "go with makefile",
+			files:    map[string]string{"go.mod": "module x", "Makefile": "all:"},
+			expected: []string{"go", "make"},
+		},
+		{
+			name:     "python requirements",
+			files:    map[string]string{"requirements.txt": ""},
+			expected: []string{"python"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			assert.Equal(t, tt.expected, Detect(dir))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		templates []string
+		expected  []manifest.Window
+		wantErr   bool
+	}{
+		{
+			name:     "blank",
+			expected: []manifest.Window{{Name: "editor", Command: "${EDITOR:-vi}"}, {Name: "shell"}},
+		},
+		{
+			name:      "go and make",
+			templates: []string{"go", "make"},
+			expected: []manifest.Window{
+				{Name: "editor", Command: "${EDITOR:-vi}"},
+				{Name: "test", Command: "go test ./..."},
+				{Name: "build", Command: "make"},
+				{Name: "shell"},
+			},
+		},
+		{
+			name: "node with pnpm",
+			files: map[string]string{
+				"package.json":   `{"scripts": {"start": "node .", "dev": "vite", "test": "vitest"}}`,
+				"pnpm-lock.yaml": "",
+			},
+			templates: []string{"node"},
+			expected: []manifest.Window{
+				{Name: "editor", Command: "${EDITOR:-vi}"},
+				{Name: "server", Command: "pnpm run dev"},
+				{Name: "test", Command: "pnpm test"},
+				{Name: "shell"},
+			},
+		},
+		{
+			name:      "duplicate windows are added once",
+			templates: []string{"go", "rust"},
+			expected: []manifest.Window{
+				{Name: "editor", Command: "${EDITOR:-vi}"},
+				{Name: "test", Command: "go test ./..."},
+				{Name: "shell"},
+			},
+		},
+		{
+			name:      "unknown template",
+			templates: []string{"cobol"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			ws, err := New("proj", ".", dir, tt.templates)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, ws.Sessions, 1)
+			assert.Equal(t, "proj", ws.Sessions[0].Name)
+			assert.Equal(t, ".", ws.Sessions[0].Root)
+			assert.Equal(t, tt.expected, ws.Sessions[0].Windows)
+		})
+	}
+}
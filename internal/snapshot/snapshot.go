@@ -0,0 +1,169 @@
+// Package snapshot keeps timestamped copies of the live workspace so a lost
+// tmux server can be rebuilt. Every snapshot is a regular workspace file.
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MSmaili/hetki/internal/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+const idLayout = "20060102-150405"
+
+type Snapshot struct {
+	ID        string
+	CreatedAt time.Time
+	Path      string
+}
+
+type Store struct {
+	Dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+func DefaultDir() (string, error) {
+	configDir, err := manifest.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "snapshots"), nil
+}
+
+// Save stores ws as a snapshot taken at now. When ws matches the latest
+// snapshot nothing is written and saved is false. Sessions are stored by
+// name so the same state always gives the same file.
+func (s *Store) Save(ws *manifest.Workspace, now time.Time) (snap Snapshot, saved bool, err error) {
+	sorted := *ws
+	sorted.Sessions = slices.SortedFunc(slices.Values(ws.Sessions), func(a, b manifest.Session) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	data, err := yaml.Marshal(&sorted)
+	if err != nil {
+		return Snapshot{}, false, fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	if latest, err := s.Find(""); err == nil {
+		if prev, err := os.ReadFile(latest.Path); err == nil && bytes.Equal(prev, data) {
+			return latest, false, nil
+		}
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return Snapshot{}, false, fmt.Errorf("create snapshot directory: %w", err)
+	}
+
+	id := now.UTC().Format(idLayout)
+	snap = Snapshot{ID: id, CreatedAt: now.UTC().Truncate(time.Second), Path: s.path(id)}
+	if err := os.WriteFile(snap.Path, data, 0o644); err != nil {
+		return Snapshot{}, false, fmt.Errorf("write snapshot: %w", err)
+	}
+	return snap, true, nil
+}
+
+// List returns all snapshots, newest first.
+func (s *Store) List() ([]Snapshot, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snaps []Snapshot
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), manifest.DefaultExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		created, err := time.Parse(idLayout, id)
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, Snapshot{ID: id, CreatedAt: created, Path: s.path(id)})
+	}
+
+	slices.SortFunc(snaps, func(a, b Snapshot) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return snaps, nil
+}
+
+// Find returns the snapshot whose ID starts with id, or the latest one when
+// id is empty.
+func (s *Store) Find(id string) (Snapshot, error) {
+	snaps, err := s.List()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if len(snaps) == 0 {
+		return Snapshot{}, fmt.Errorf("no snapshots found in %s\nHint: Take one with 'hetki snapshot'", s.Dir)
+	}
+	if id == "" {
+		return snaps[0], nil
+	}
+
+	var matches []Snapshot
+	for _, snap := range snaps {
+		if snap.ID == id {
+			return snap, nil
+		}
+		if strings.HasPrefix(snap.ID, id) {
+			matches = append(matches, snap)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Snapshot{}, fmt.Errorf("snapshot not found: %s\nHint: List snapshots with 'hetki snapshot --list'", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return Snapshot{}, fmt.Errorf("snapshot id %q is ambiguous (%d matches)\nHint: Use more of the id, e.g. %s", id, len(matches), matches[0].ID)
+	}
+}
+
+// Load reads the workspace stored in snap.
+func (s *Store) Load(snap Snapshot) (*manifest.Workspace, error) {
+	return manifest.NewFileLoader(snap.Path).Load()
+}
+
+// Prune removes snapshots beyond the newest keep and those older than
+// maxAge. Zero disables either limit. The latest snapshot is never removed.
+func (s *Store) Prune(keep int, maxAge time.Duration, now time.Time) ([]Snapshot, error) {
+	snaps, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []Snapshot
+	for i, snap := range snaps {
+		if i == 0 {
+			continue
+		}
+		tooMany := keep > 0 && i >= keep
+		tooOld := maxAge > 0 && now.Sub(snap.CreatedAt) > maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(snap.Path); err != nil {
+			return removed, fmt.Errorf("remove snapshot %s: %w", snap.ID, err)
+		}
+		removed = append(removed, snap)
+	}
+	return removed, nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.Dir, id+manifest.DefaultExt)
+}
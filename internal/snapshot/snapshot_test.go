@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MSmaili/hetki/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func workspace(session string) *manifest.Workspace {
+	return &manifest.Workspace{Sessions: []manifest.Session{{
+		Name:    session,
+		Windows: []manifest.Window{{Name: "editor", Path: "/code"}},
+	}}}
+}
+
+func TestStoreSave(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	first, saved, err := store.Save(workspace("dev"), now)
+	require.NoError(t, err)
+	assert.True(t, saved)
+	assert.Equal(t, "20260301-120000", first.ID)
+
+	_, saved, err = store.Save(workspace("dev"), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, saved, "unchanged state should not be saved again")
+
+	second, saved, err := store.Save(workspace("api"), now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	snaps, err := store.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{second.ID, first.ID}, ids(snaps))
+
+	ws, err := store.Load(second)
+	require.NoError(t, err)
+	assert.Equal(t, "api", ws.Sessions[0].Name)
+}
+
+func TestStoreSaveIgnoresSessionOrder(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dev, api := workspace("dev").Sessions[0], workspace("api").Sessions[0]
+
+	_, saved, err := store.Save(&manifest.Workspace{Sessions: []manifest.Session{dev, api}}, now)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	_, saved, err = store.Save(&manifest.Workspace{Sessions: []manifest.Session{api, dev}}, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, saved, "the same sessions in another order should not be saved again")
+}
+
+func TestStoreFind(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, name := range []string{"a", "b", "c"} {
+		_, _, err := store.Save(workspace(name), now.Add(time.Duration(i)*time.Hour))
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{"latest", "", "20260301-140000", false},
+		{"exact", "20260301-120000", "20260301-120000", false},
+		{"prefix", "20260301-13", "20260301-130000", false},
+		{"ambiguous", "20260301", "", true},
+		{"unknown", "2025", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap, err := store.Find(tt.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, snap.ID)
+		})
+	}
+}
+
+func TestStoreFindEmpty(t *testing.T) {
+	_, err := NewStore(t.TempDir()).Find("")
+	assert.ErrorContains(t, err, "no snapshots found")
+}
+
+func TestStorePrune(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		keep   int
+		maxAge time.Duration
+		want   []string
+	}{
+		{"no limits", 0, 0, []string{"20260301-120000", "20260301-110000", "20260301-100000", "20260301-090000"}},
+		{"keep two", 2, 0, []string{"20260301-120000", "20260301-110000"}},
+		{"max age", 0, 90 * time.Minute, []string{"20260301-120000", "20260301-110000"}},
+		{"latest always kept", 0, time.Minute, []string{"20260301-120000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore(t.TempDir())
+			for i, name := range []string{"a", "b", "c", "d"} {
+				_, _, err := store.Save(workspace(name), now.Add(time.Duration(i-3)*time.Hour))
+				require.NoError(t, err)
+			}
+
+			_, err := store.Prune(tt.keep, tt.maxAge, now.Add(time.Minute))
+			require.NoError(t, err)
+
+			snaps, err := store.List()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(snaps))
+		})
+	}
+}
+
+func ids(snaps []Snapshot) []string {
+	out := make([]string, len(snaps))
+	for i, s := range snaps {
+		out[i] = s.ID
+	}
+	return out
+}
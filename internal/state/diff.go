@@ -30,3 +30,7 @@ func Compare(desired, actual *State) Diff {
 
 	return diff
 }
+
+func (d Diff) IsEmpty() bool {
+	return d.Sessions.IsEmpty() && len(d.Windows) == 0
+}
@@ -107,6 +107,20 @@ func TestCompareWindowsMismatchedPaneCount(t *testing.T) {
 	assert.Equal(t, 1, len(diff.Windows["s"].Mismatched[0].Actual.Panes))
 }
 
+func TestCompareWindowsWithoutPanesMatchSinglePane(t *testing.T) {
+	desired := &State{Sessions: map[string]*Session{
+		"s": {Name: "s", Windows: []*Window{{Name: "editor", Path: "/home"}}},
+	}}
+
+	actual := &State{Sessions: map[string]*Session{
+		"s": {Name: "s", Windows: []*Window{{Name: "editor", Path: "/home", Panes: []*Pane{{Path: "/home"}}}}},
+	}}
+
+	diff := Compare(desired, actual)
+
+	assert.NotContains(t, diff.Windows, "s")
+}
+
 func TestCompareWindowsForMissingSession(t *testing.T) {
 	desired := &State{Sessions: map[string]*Session{
 		"new-session": {
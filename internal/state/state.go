@@ -13,14 +13,17 @@ type Window struct {
 	Name   string
 	Path   string
 	Layout string
+	Focus  bool
 	Panes  []*Pane
 }
 
 type Pane struct {
-	Index   int
-	Path    string
-	Command string
-	Zoom    bool
+	Index      int
+	Path       string
+	Command    string
+	Zoom       bool
+	Focus      bool
+	Scrollback string
 }
 
 func NewState() *State {
@@ -52,7 +52,13 @@ func compareSessionWindows(desired, actual []*Window) ItemDiff[Window] {
 }
 
 func windowsMatch(desired, actual *Window) bool {
-	return len(desired.Panes) == len(actual.Panes)
+	return PaneCount(desired) == PaneCount(actual)
+}
+
+// PaneCount returns the number of panes in w. A window always has at least
+// one pane, even when the manifest lists none.
+func PaneCount(w *Window) int {
+	return max(1, len(w.Panes))
 }
 
 func windowsByKey(windows []*Window) map[windowKey]*Window {
@@ -0,0 +1,64 @@
+// Package yamlnode edits mappings in a parsed yaml.Node tree, so a file can
+// be rewritten without losing its comments, key order or indentation.
+package yamlnode
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MappingValue returns the value stored under key in the mapping m, or nil
+// when m is not a mapping or has no such key.
+func MappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// SetValue stores value under key in the mapping m, replacing the old value
+// in place or appending the key at the end.
+func SetValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			// Keep comments attached to the old value.
+			value.HeadComment = m.Content[i+1].HeadComment
+			value.LineComment = m.Content[i+1].LineComment
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
+
+// DeleteKey removes key and its value from the mapping m.
+func DeleteKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// DetectIndent returns the indentation width used in src, falling back to
+// the width yaml.Marshal uses.
+func DetectIndent(src []byte) int {
+	for _, line := range strings.Split(string(src), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		return len(line) - len(trimmed)
+	}
+	return 4
+}
@@ -0,0 +1,69 @@
+package yamlnode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func parseMapping(t *testing.T, src string) *yaml.Node {
+	t.Helper()
+	var doc yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(src), &doc))
+	return doc.Content[0]
+}
+
+func encode(t *testing.T, n *yaml.Node) string {
+	t.Helper()
+	out, err := yaml.Marshal(n)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestMappingValue(t *testing.T) {
+	m := parseMapping(t, "a: 1\nb: [x]\n")
+
+	assert.Equal(t, "1", MappingValue(m, "a").Value)
+	assert.Equal(t, yaml.SequenceNode, MappingValue(m, "b").Kind)
+	assert.Nil(t, MappingValue(m, "c"))
+	assert.Nil(t, MappingValue(MappingValue(m, "b"), "x"))
+}
+
+func TestSetValue(t *testing.T) {
+	m := parseMapping(t, "a: 1 # keep\nb: 2\n")
+
+	SetValue(m, "a", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "3"})
+	SetValue(m, "c", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "new"})
+
+	assert.Equal(t, "a: 3 # keep\nb: 2\nc: new\n", encode(t, m))
+}
+
+func TestDeleteKey(t *testing.T) {
+	m := parseMapping(t, "a: 1\nb: 2\nc: 3\n")
+
+	DeleteKey(m, "b")
+	DeleteKey(m, "missing")
+
+	assert.Equal(t, "a: 1\nc: 3\n", encode(t, m))
+}
+
+func TestDetectIndent(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"two spaces", "a:\n  b: 1\n", 2},
+		{"skips comments", "a:\n    # note\n  b: 1\n", 2},
+		{"flat document", "a: 1\n", 4},
+		{"empty", "", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectIndent([]byte(tt.src)))
+		})
+	}
+}